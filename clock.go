@@ -0,0 +1,405 @@
+package vanatime
+
+import (
+	"errors"
+	"sync"
+)
+
+// A Clock provides the current Vana'diel time and the timers and tickers
+// driven by it. Code which depends on a Clock rather than on the package
+// level functions can be tested with a ManualClock.
+type Clock interface {
+	// Now returns the current Vana'diel time.
+	Now() Time
+
+	// Since returns the time elapsed since t.
+	Since(t Time) Duration
+
+	// Until returns the duration until t.
+	Until(t Time) Duration
+
+	// Sleep pauses the current goroutine for at least the duration d.
+	Sleep(d Duration)
+
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(d Duration) <-chan Time
+
+	// NewTimer creates a new Timer that will send the current time on its
+	// channel after at least duration d.
+	NewTimer(d Duration) *Timer
+
+	// AfterFunc waits for the duration to elapse and then calls f.
+	AfterFunc(d Duration, f func()) *Timer
+
+	// NewTimerAt creates a new Timer that will send the current time on
+	// its channel at the time t.
+	NewTimerAt(t Time) *Timer
+
+	// AfterFuncAt waits until the time t and then calls f.
+	AfterFuncAt(t Time, f func()) *Timer
+
+	// NewTicker returns a new Ticker containing a channel that will send
+	// the time with a period specified by the duration argument.
+	NewTicker(d Duration) *Ticker
+
+	// NewAlignedTicker returns a new Ticker containing a channel that will
+	// send every boundary time which is a multiple of unit.
+	NewAlignedTicker(unit Duration) *Ticker
+}
+
+// SystemClock is the Clock backed by the Earth wall clock of the system.
+// Its methods are equivalent to the package level functions.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() Time                             { return Now() }
+func (systemClock) Since(t Time) Duration                 { return Since(t) }
+func (systemClock) Until(t Time) Duration                 { return Until(t) }
+func (systemClock) Sleep(d Duration)                      { Sleep(d) }
+func (systemClock) After(d Duration) <-chan Time          { return After(d) }
+func (systemClock) NewTimer(d Duration) *Timer            { return NewTimer(d) }
+func (systemClock) AfterFunc(d Duration, f func()) *Timer { return AfterFunc(d, f) }
+func (systemClock) NewTimerAt(t Time) *Timer              { return NewTimerAt(t) }
+func (systemClock) AfterFuncAt(t Time, f func()) *Timer   { return AfterFuncAt(t, f) }
+func (systemClock) NewTicker(d Duration) *Ticker          { return NewTicker(d) }
+func (systemClock) NewAlignedTicker(d Duration) *Ticker   { return NewAlignedTicker(d) }
+
+// A ManualClock is a Clock whose time only moves when it is advanced
+// explicitly. It is intended for testing code which uses timers and tickers
+// without waiting for the real time to pass.
+//
+// Timers and tickers created by a ManualClock fire while Advance or Set
+// moves the clock past their deadlines, in the order of their deadlines.
+// Functions registered with AfterFunc are called synchronously by the
+// goroutine advancing the clock, so they must not block on it.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     Time
+	seq     uint64
+	waiters []*manualWaiter
+}
+
+type manualWaiter struct {
+	when   Time
+	period Duration // non-zero for tickers
+	seq    uint64   // registration order, to break ties
+	fire   func(now Time)
+}
+
+// NewManualClock returns a ManualClock set to t.
+func NewManualClock(t Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed since t.
+func (c *ManualClock) Since(t Time) Duration {
+	return c.Now().Sub(t)
+}
+
+// Until returns the duration until t.
+func (c *ManualClock) Until(t Time) Duration {
+	return t.Sub(c.Now())
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+// A negative or zero duration causes Sleep to return immediately.
+func (c *ManualClock) Sleep(d Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.After(d)
+}
+
+// After returns a channel which receives the time of the clock once it
+// has been advanced by at least d.
+func (c *ManualClock) After(d Duration) <-chan Time {
+	return c.NewTimer(d).C
+}
+
+// NewTimer creates a new Timer that will send the time of the clock on its
+// channel once the clock has been advanced by at least d.
+func (c *ManualClock) NewTimer(d Duration) *Timer {
+	return c.newTimer(d, sendTime)
+}
+
+// AfterFunc returns a Timer that calls f once the clock has been advanced
+// by at least d. f is called by the goroutine advancing the clock.
+func (c *ManualClock) AfterFunc(d Duration, f func()) *Timer {
+	return c.newTimer(d, func(ch chan<- Time, t Time) {
+		f()
+	})
+}
+
+// NewTimerAt creates a new Timer that will send the time of the clock on
+// its channel once the clock has been advanced to t. If t is not after the
+// time of the clock, the timer fires on the next Advance or Set.
+func (c *ManualClock) NewTimerAt(t Time) *Timer {
+	return c.newTimerAt(t, sendTime)
+}
+
+// AfterFuncAt returns a Timer that calls f once the clock has been
+// advanced to t. f is called by the goroutine advancing the clock.
+func (c *ManualClock) AfterFuncAt(t Time, f func()) *Timer {
+	return c.newTimerAt(t, func(ch chan<- Time, t Time) {
+		f()
+	})
+}
+
+func (c *ManualClock) newTimer(d Duration, f timerFunc) *Timer {
+	t := c.timer(f)
+	c.add(t.w, d)
+	return t
+}
+
+func (c *ManualClock) newTimerAt(deadline Time, f timerFunc) *Timer {
+	t := c.timer(f)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(t.w, deadline)
+	return t
+}
+
+// timer returns a new Timer which is not registered yet.
+func (c *ManualClock) timer(f timerFunc) *Timer {
+	ch := make(chan Time, 1)
+	t := &Timer{
+		C:      ch,
+		c:      ch,
+		f:      f,
+		manual: c,
+	}
+	t.w = &manualWaiter{
+		fire: func(now Time) {
+			t.f(t.c, now)
+		},
+	}
+	return t
+}
+
+// NewTicker returns a new Ticker which sends the time of the clock on its
+// channel each time the clock passes a multiple of d. The duration d must be
+// greater than zero; if not, NewTicker will panic.
+func (c *ManualClock) NewTicker(d Duration) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTicker"))
+	}
+
+	ch := make(chan Time, 1)
+	t := &Ticker{
+		C:      ch,
+		c:      ch,
+		manual: c,
+	}
+	t.w = &manualWaiter{
+		period: d,
+		fire: func(now Time) {
+			sendTime(t.c, now)
+		},
+	}
+	c.add(t.w, d)
+	return t
+}
+
+// NewAlignedTicker returns a new Ticker which sends every boundary time
+// which is a multiple of unit on its channel as the clock passes it. The unit
+// must be greater than zero; if not, NewAlignedTicker will panic.
+//
+// Like the ticker of the package-level NewAlignedTicker, the ticker does not
+// drop ticks: if the clock passes several boundaries before they are
+// received, such as by advancing it by a day for an hourly ticker, the
+// boundaries are queued and sent in order. Stop the ticker to discard the
+// queued boundaries.
+func (c *ManualClock) NewAlignedTicker(unit Duration) *Ticker {
+	if unit <= 0 {
+		panic(errors.New("non-positive interval for NewAlignedTicker"))
+	}
+
+	ch := make(chan Time, 1)
+	t := &Ticker{
+		C:      ch,
+		c:      ch,
+		stop:   make(chan struct{}),
+		manual: c,
+	}
+	q := &tickQueue{ready: make(chan struct{}, 1)}
+	t.w = &manualWaiter{
+		period: unit,
+		fire: func(now Time) {
+			q.push(t.c, now)
+		},
+	}
+	stop := t.stop
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		q.run(t.c, stop)
+	}()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(t.w, c.now.Truncate(unit).Add(unit))
+	return t
+}
+
+// A tickQueue holds the ticks of a manual aligned ticker which could not be
+// sent without blocking the goroutine advancing the clock.
+type tickQueue struct {
+	mu      sync.Mutex
+	pending []Time        // the head is being sent by run
+	ready   chan struct{} // signals run that pending is not empty
+}
+
+// push sends v on c if nothing is queued and c is not full, or else queues
+// it for run.
+func (q *tickQueue) push(c chan Time, v Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		select {
+		case c <- v:
+			return
+		default:
+		}
+	}
+	q.pending = append(q.pending, v)
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run sends the queued ticks on c in order until stop is closed.
+func (q *tickQueue) run(c chan Time, stop <-chan struct{}) {
+	for {
+		select {
+		case <-q.ready:
+		case <-stop:
+			return
+		}
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			v := q.pending[0]
+			q.mu.Unlock()
+
+			select {
+			case c <- v:
+			case <-stop:
+				return
+			}
+
+			q.mu.Lock()
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+		}
+	}
+}
+
+// Advance moves the clock forward by d, firing the timers and tickers
+// whose deadlines are reached on the way.
+func (c *ManualClock) Advance(d Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing the timers and tickers whose deadlines
+// are not after t. Setting the clock backward does not fire anything.
+func (c *ManualClock) Set(t Time) {
+	for {
+		c.mu.Lock()
+		w := c.next(t)
+		if w == nil {
+			c.now = t
+			c.mu.Unlock()
+			return
+		}
+		if w.when.After(c.now) {
+			c.now = w.when
+		}
+		now := c.now
+		if w.period > 0 {
+			w.when = w.when.Add(w.period)
+		} else {
+			c.remove(w)
+		}
+		c.mu.Unlock()
+
+		w.fire(now)
+	}
+}
+
+// Pending returns the number of timers and tickers waiting to fire.
+// Tests can use it to wait until a goroutine has started to sleep.
+func (c *ManualClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// next returns the earliest waiter whose deadline is not after t.
+func (c *ManualClock) next(t Time) *manualWaiter {
+	var next *manualWaiter
+	for _, w := range c.waiters {
+		if w.when.After(t) {
+			continue
+		}
+		if next == nil || w.when.Before(next.when) || (w.when.Equal(next.when) && w.seq < next.seq) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (c *ManualClock) add(w *manualWaiter, d Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(w, c.now.Add(d))
+}
+
+// insert registers w to fire at when. c.mu must be held.
+func (c *ManualClock) insert(w *manualWaiter, when Time) {
+	c.seq++
+	w.seq = c.seq
+	w.when = when
+	c.waiters = append(c.waiters, w)
+}
+
+// remove removes w and reports whether it was pending.
+func (c *ManualClock) remove(w *manualWaiter) bool {
+	for i, v := range c.waiters {
+		if v == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ManualClock) stop(w *manualWaiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remove(w)
+}
+
+func (c *ManualClock) deadline(w *manualWaiter) Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return w.when
+}
+
+func (c *ManualClock) reset(w *manualWaiter, d Duration) bool {
+	active := c.stop(w)
+	c.add(w, d)
+	return active
+}
@@ -0,0 +1,248 @@
+package vanatime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+var _ vanatime.Clock = vanatime.SystemClock
+var _ vanatime.Clock = (*vanatime.ManualClock)(nil)
+
+func TestManualClockNow(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 0, 0, 0, 0)
+	c := vanatime.NewManualClock(start)
+
+	if got := c.Now(); !got.Equal(start) {
+		t.Fatalf(`want "%v", but "%v"`, start, got)
+	}
+
+	c.Advance(vanatime.Hour)
+	if got, want := c.Now(), start.Add(vanatime.Hour); !got.Equal(want) {
+		t.Fatalf(`want "%v", but "%v"`, want, got)
+	}
+	if got := c.Since(start); got != vanatime.Hour {
+		t.Fatalf(`want "%v", but "%v"`, vanatime.Hour, got)
+	}
+	if got := c.Until(start.Add(vanatime.Day)); got != 23*vanatime.Hour {
+		t.Fatalf(`want "%v", but "%v"`, 23*vanatime.Hour, got)
+	}
+}
+
+func TestManualClockTimer(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 0, 0, 0, 0)
+	c := vanatime.NewManualClock(start)
+	timer := c.NewTimer(vanatime.Hour)
+
+	c.Advance(59 * vanatime.Minute)
+	select {
+	case v := <-timer.C:
+		t.Fatalf("timer fired early at %v", v)
+	default:
+	}
+
+	c.Advance(2 * vanatime.Minute)
+	select {
+	case v := <-timer.C:
+		if want := start.Add(vanatime.Hour); !v.Equal(want) {
+			t.Errorf(`want "%v", but "%v"`, want, v)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+
+	if timer.Stop() {
+		t.Errorf("Stop of an expired timer returned true")
+	}
+	if timer.Reset(vanatime.Minute) {
+		t.Errorf("Reset of an expired timer returned true")
+	}
+	if !timer.Stop() {
+		t.Errorf("Stop of an active timer returned false")
+	}
+	c.Advance(vanatime.Hour)
+	select {
+	case v := <-timer.C:
+		t.Fatalf("stopped timer fired at %v", v)
+	default:
+	}
+	if c.Pending() != 0 {
+		t.Errorf("want no pending timers, but %d", c.Pending())
+	}
+}
+
+func TestManualClockOrder(t *testing.T) {
+	c := vanatime.NewManualClock(vanatime.Date(1313, 4, 13, 0, 0, 0, 0))
+
+	var got []string
+	record := func(name string) func() {
+		return func() {
+			got = append(got, name+" "+c.Now().Strftime("%H:%M"))
+		}
+	}
+	c.AfterFunc(3*vanatime.Hour, record("c"))
+	c.AfterFunc(vanatime.Hour, record("a"))
+	c.AfterFunc(2*vanatime.Hour, record("b1"))
+	c.AfterFunc(2*vanatime.Hour, record("b2"))
+	c.AfterFunc(vanatime.Hour, func() {
+		// timers registered while firing are honoured in the same Advance
+		c.AfterFunc(30*vanatime.Minute, record("a+30m"))
+	})
+
+	c.Advance(5 * vanatime.Hour)
+
+	want := []string{"a 01:00", "a+30m 01:30", "b1 02:00", "b2 02:00", "c 03:00"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, but %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, but %v", want, got)
+		}
+	}
+}
+
+func TestManualClockTicker(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 0, 0, 0, 0)
+	c := vanatime.NewManualClock(start)
+	ticker := c.NewTicker(vanatime.Hour)
+
+	for i := 1; i <= 3; i++ {
+		c.Advance(vanatime.Hour)
+		select {
+		case v := <-ticker.C:
+			if want := start.Add(vanatime.Duration(i) * vanatime.Hour); !v.Equal(want) {
+				t.Errorf(`[%d]: want "%v", but "%v"`, i, want, v)
+			}
+		default:
+			t.Fatalf("[%d]: ticker did not fire", i)
+		}
+	}
+
+	// slow receivers lose ticks
+	c.Advance(3 * vanatime.Hour)
+	if v := <-ticker.C; !v.Equal(start.Add(4 * vanatime.Hour)) {
+		t.Errorf(`want "%v", but "%v"`, start.Add(4*vanatime.Hour), v)
+	}
+
+	ticker.Stop()
+	c.Advance(vanatime.Hour)
+	select {
+	case v := <-ticker.C:
+		t.Fatalf("stopped ticker fired at %v", v)
+	default:
+	}
+}
+
+func TestManualClockSleep(t *testing.T) {
+	c := vanatime.NewManualClock(vanatime.Date(1313, 4, 13, 0, 0, 0, 0))
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(vanatime.Day)
+		close(done)
+	}()
+
+	for c.Pending() == 0 {
+		vanatime.Sleep(vanatime.Millisecond)
+	}
+	c.Advance(vanatime.Day)
+	<-done
+}
+
+func TestManualClockAlignedTicker(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	c := vanatime.NewManualClock(start)
+	ticker := c.NewAlignedTicker(vanatime.Hour)
+	defer ticker.Stop()
+
+	c.Advance(30 * vanatime.Minute)
+	select {
+	case v := <-ticker.C:
+		t.Fatalf("ticker fired at %v", v)
+	default:
+	}
+
+	for i := 0; i < 3; i++ {
+		c.Advance(vanatime.Hour)
+		want := vanatime.Date(1313, 4, 13, 22+i, 0, 0, 0)
+		select {
+		case v := <-ticker.C:
+			if !v.Equal(want) {
+				t.Errorf(`[%d]: want "%v", but "%v"`, i, want, v)
+			}
+		default:
+			t.Fatalf("[%d]: ticker did not fire", i)
+		}
+	}
+}
+
+func TestManualClockAlignedTickerQueue(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	c := vanatime.NewManualClock(start)
+	ticker := c.NewAlignedTicker(vanatime.Hour)
+
+	// every boundary passed by a single Advance is sent, in order
+	c.Advance(vanatime.Day)
+	for i := 0; i < 24; i++ {
+		want := vanatime.Date(1313, 4, 13, 22, 0, 0, 0).Add(vanatime.Duration(i) * vanatime.Hour)
+		select {
+		case v := <-ticker.C:
+			if !v.Equal(want) {
+				t.Errorf(`[%d]: want "%v", but "%v"`, i, want, v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("[%d]: ticker did not fire", i)
+		}
+	}
+	select {
+	case v := <-ticker.C:
+		t.Fatalf("ticker fired at %v", v)
+	default:
+	}
+
+	// Stop discards the queued boundaries
+	c.Advance(vanatime.Day)
+	ticker.Stop()
+	<-ticker.C
+	select {
+	case v := <-ticker.C:
+		t.Errorf("stopped ticker fired at %v", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestManualClockTimerAt(t *testing.T) {
+	start := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	c := vanatime.NewManualClock(start)
+	deadline := vanatime.Date(1314, 1, 1, 0, 0, 0, 0)
+	timer := c.NewTimerAt(deadline)
+	called := false
+	c.AfterFuncAt(deadline, func() {
+		called = true
+	})
+
+	if got := timer.Deadline(); !got.Equal(deadline) {
+		t.Errorf(`want "%v", but "%v"`, deadline, got)
+	}
+
+	c.Set(deadline.Add(-1))
+	select {
+	case v := <-timer.C:
+		t.Fatalf("timer fired at %v", v)
+	default:
+	}
+
+	c.Advance(vanatime.Hour)
+	select {
+	case v := <-timer.C:
+		if !v.Equal(deadline) {
+			t.Errorf(`want "%v", but "%v"`, deadline, v)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if !called {
+		t.Error("function was not called")
+	}
+}
@@ -0,0 +1,433 @@
+// Command vanaclock prints the Vana'diel time.
+//
+// Usage:
+//
+//     vanaclock [now] [-f format] [-l locale] [-json]
+//     vanaclock convert [-l locale] [-json] (-earth time | -vana time)
+//     vanaclock moon [-at time] [-l locale] [-json]
+//     vanaclock phases [-n count] [-phase name] [-l locale] [-json]
+//     vanaclock days [-n count] [-weekday name] [-l locale] [-json]
+//     vanaclock watch [-n count] [-plain] [-l locale] [-json]
+//
+// Without a command, vanaclock prints the current Vana'diel time, formatted
+// by the Strftime format given with -f. convert converts an Earth time
+// (RFC 3339, or "2006-01-02 15:04:05" in the local time zone) to Vana'diel
+// time, or a Vana'diel time ("1313-04-13 21:20:27") to Earth time. moon
+// prints the details of the moon, phases lists the upcoming moon phase
+// changes, and days lists the upcoming starts of the Vana'diel days.
+//
+// watch redraws a dashboard at every Vana'diel minute, with the time in big
+// digits, the day of the week colored by its element, the moon, and the
+// time left until the next day, week and moon phase. If the output is not a
+// terminal, or with -plain, it prints a line of plain text instead.
+//
+// The -l flag selects the locale of the names (en, ja, fr, de), and -json
+// prints the result as JSON.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, vanatime.SystemClock))
+}
+
+// earthLayout is the layout of the Earth times in the text output.
+const earthLayout = "2006-01-02 15:04:05 MST"
+
+type cli struct {
+	stdout io.Writer
+	stderr io.Writer
+	clock  vanatime.Clock
+
+	locale string
+	json   bool
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(c *cli, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"now", "[-f format] [-l locale] [-json]", (*cli).now},
+	{"convert", "[-l locale] [-json] (-earth time | -vana time)", (*cli).convert},
+	{"moon", "[-at time] [-l locale] [-json]", (*cli).moon},
+	{"phases", "[-n count] [-phase name] [-l locale] [-json]", (*cli).phases},
+	{"days", "[-n count] [-weekday name] [-l locale] [-json]", (*cli).days},
+	{"watch", "[-n count] [-plain] [-l locale] [-json]", (*cli).watch},
+}
+
+// errUsage is returned by commands for invalid arguments, after printing
+// the problem.
+var errUsage = errors.New("usage")
+
+// run runs the command line and returns the exit status.
+func run(args []string, stdout, stderr io.Writer, clock vanatime.Clock) int {
+	c := &cli{stdout: stdout, stderr: stderr, clock: clock}
+
+	cmd := commands[0]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		found := false
+		for _, v := range commands {
+			if v.name == args[0] {
+				cmd, found = v, true
+			}
+		}
+		if !found {
+			fmt.Fprintf(stderr, "vanaclock: unknown command %q\n", args[0])
+			c.usage()
+			return 2
+		}
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("vanaclock "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: vanaclock %s %s\n", cmd.name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&c.locale, "l", "en", "locale of the names (en, ja, fr, de)")
+	fs.BoolVar(&c.json, "json", false, "print the result as JSON")
+
+	err := cmd.run(c, fs, args)
+	switch {
+	case err == nil:
+		return 0
+	case err == errUsage || err == flag.ErrHelp:
+		return 2
+	default:
+		fmt.Fprintf(stderr, "vanaclock: %v\n", err)
+		return 1
+	}
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  vanaclock %s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// parse parses the flags of a command, which takes no arguments.
+func (c *cli) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.stderr, "vanaclock %s: unexpected argument %q\n", fs.Name(), fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (c *cli) usageError(fs *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(c.stderr, "%s: %s\n", fs.Name(), fmt.Sprintf(format, args...))
+	fs.Usage()
+	return errUsage
+}
+
+func (c *cli) printJSON(v interface{}) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// timeInfo is the JSON form of a time.
+type timeInfo struct {
+	Vana      vanatime.Time `json:"vana"`
+	Earth     time.Time     `json:"earth"`
+	Weekday   string        `json:"weekday"`
+	Element   string        `json:"element"`
+	Moon      string        `json:"moon"`
+	Percent   int           `json:"percent"`
+	Formatted string        `json:"formatted,omitempty"`
+}
+
+func (c *cli) timeInfo(t vanatime.Time) timeInfo {
+	return timeInfo{
+		Vana:    t,
+		Earth:   t.Earth(),
+		Weekday: t.Weekday().StringLocale(c.locale),
+		Element: t.Weekday().Element().StringLocale(c.locale),
+		Moon:    t.Moon().Phase().StringLocale(c.locale),
+		Percent: t.Moon().Percent(),
+	}
+}
+
+func (c *cli) now(fs *flag.FlagSet, args []string) error {
+	format := fs.String("f", "", "Strftime format of the time")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	t := c.clock.Now()
+	if c.json {
+		info := c.timeInfo(t)
+		if *format != "" {
+			info.Formatted = t.StrftimeLocale(*format, c.locale)
+		}
+		return c.printJSON(info)
+	}
+	if *format != "" {
+		fmt.Fprintln(c.stdout, t.StrftimeLocale(*format, c.locale))
+	} else {
+		fmt.Fprintln(c.stdout, t.StringLocale(c.locale))
+	}
+	return nil
+}
+
+func (c *cli) convert(fs *flag.FlagSet, args []string) error {
+	earth := fs.String("earth", "", "Earth time to convert to Vana'diel time")
+	vana := fs.String("vana", "", "Vana'diel time to convert to Earth time")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if (*earth == "") == (*vana == "") {
+		return c.usageError(fs, "either -earth or -vana is required")
+	}
+
+	var t vanatime.Time
+	if *earth != "" {
+		et, err := parseEarth(*earth)
+		if err != nil {
+			return err
+		}
+		t = vanatime.FromEarth(et)
+	} else {
+		var err error
+		if t, err = parseVana(*vana); err != nil {
+			return err
+		}
+	}
+
+	if c.json {
+		return c.printJSON(c.timeInfo(t))
+	}
+	if *earth != "" {
+		fmt.Fprintln(c.stdout, t.StringLocale(c.locale))
+	} else {
+		fmt.Fprintln(c.stdout, t.Earth().Format(earthLayout))
+	}
+	return nil
+}
+
+// parseEarth parses an Earth time in RFC 3339, or in the local time zone.
+func parseEarth(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid Earth time %q", s)
+}
+
+// parseVana parses a Vana'diel date and time, or date.
+func parseVana(s string) (vanatime.Time, error) {
+	for _, layout := range []string{vanatime.DateTimeMicro, vanatime.DateTime, vanatime.DateOnly} {
+		if t, err := vanatime.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return vanatime.Time{}, fmt.Errorf("invalid Vana'diel time %q", s)
+}
+
+// moonInfo is the JSON form of the moon.
+type moonInfo struct {
+	Time          vanatime.Time `json:"time"`
+	Phase         string        `json:"phase"`
+	PhaseIndex    int           `json:"phase_index"`
+	Percent       int           `json:"percent"`
+	PercentFloat  float64       `json:"percent_float"`
+	Age           int           `json:"age"`
+	Cycle         int           `json:"cycle"`
+	Waxing        bool          `json:"waxing"`
+	PhaseStart    vanatime.Time `json:"phase_start"`
+	PhaseEnd      vanatime.Time `json:"phase_end"`
+	PhaseEndEarth time.Time     `json:"phase_end_earth"`
+}
+
+func (c *cli) moon(fs *flag.FlagSet, args []string) error {
+	at := fs.String("at", "", "Vana'diel time of the moon (default now)")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	t := c.clock.Now()
+	if *at != "" {
+		var err error
+		if t, err = parseVana(*at); err != nil {
+			return err
+		}
+	}
+
+	m := t.Moon()
+	info := moonInfo{
+		Time:          t,
+		Phase:         m.Phase().StringLocale(c.locale),
+		PhaseIndex:    int(m.Phase()),
+		Percent:       m.Percent(),
+		PercentFloat:  m.PercentFloat(),
+		Age:           m.Age(),
+		Cycle:         m.Cycle(),
+		Waxing:        m.Waxing(),
+		PhaseStart:    vanatime.PhaseStart(t),
+		PhaseEnd:      vanatime.PhaseEnd(t),
+		PhaseEndEarth: vanatime.PhaseEnd(t).Earth(),
+	}
+	if c.json {
+		return c.printJSON(info)
+	}
+
+	waxing := "waning"
+	if info.Waxing {
+		waxing = "waxing"
+	}
+	fmt.Fprintf(c.stdout, "Phase:   %s\n", info.Phase)
+	fmt.Fprintf(c.stdout, "Percent: %d%% (%.2f%%), %s\n", info.Percent, info.PercentFloat, waxing)
+	fmt.Fprintf(c.stdout, "Age:     day %d of %d, cycle %d\n", info.Age, vanatime.MoonCycleDays, info.Cycle)
+	fmt.Fprintf(c.stdout, "Period:  %s - %s\n", info.PhaseStart.Strftime("%F %T"), info.PhaseEnd.Strftime("%F %T"))
+	fmt.Fprintf(c.stdout, "Ends:    %s (in %v Vana'diel)\n", info.PhaseEndEarth.Format(earthLayout), info.PhaseEnd.Sub(t).Truncate(vanatime.Minute))
+	return nil
+}
+
+// eventInfo is the JSON form of a phase change or a day start.
+type eventInfo struct {
+	Vana    vanatime.Time `json:"vana"`
+	Earth   time.Time     `json:"earth"`
+	Name    string        `json:"name"`
+	Index   int           `json:"index"`
+	Element string        `json:"element,omitempty"`
+}
+
+func (c *cli) printEvents(events []eventInfo) error {
+	if c.json {
+		return c.printJSON(events)
+	}
+	for _, e := range events {
+		name := e.Name
+		if e.Element != "" {
+			name += " (" + e.Element + ")"
+		}
+		fmt.Fprintf(c.stdout, "%s  %-24s %s\n", e.Vana.Strftime("%F %T"), name, e.Earth.Format(earthLayout))
+	}
+	return nil
+}
+
+func (c *cli) phases(fs *flag.FlagSet, args []string) error {
+	n := fs.Int("n", 12, "number of phase changes")
+	name := fs.String("phase", "", "list only the starts of the moon phase (e.g. \"Full Moon\" or 6)")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	var only *vanatime.MoonPhase
+	if *name != "" {
+		p, ok := parsePhase(*name)
+		if !ok {
+			return c.usageError(fs, "unknown moon phase %q", *name)
+		}
+		only = &p
+	}
+
+	var events []eventInfo
+	t := c.clock.Now()
+	for len(events) < *n {
+		if only != nil {
+			t = vanatime.NextPhase(t, *only)
+		} else {
+			t = vanatime.PhaseEnd(t)
+		}
+		p := t.Moon().Phase()
+		events = append(events, eventInfo{
+			Vana:  t,
+			Earth: t.Earth(),
+			Name:  p.StringLocale(c.locale),
+			Index: int(p),
+		})
+	}
+	return c.printEvents(events)
+}
+
+// parsePhase parses the name or the number of a moon phase. The name is
+// compared ignoring case and spaces, and matches the first phase of that
+// name.
+func parsePhase(s string) (vanatime.MoonPhase, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return vanatime.MoonPhase(n), n >= 0 && n < 12
+	}
+	key := strings.ToLower(strings.Replace(s, " ", "", -1))
+	for p := vanatime.NewMoon; p <= vanatime.WaningCrescent2; p++ {
+		if strings.ToLower(strings.Replace(p.String(), " ", "", -1)) == key {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+func (c *cli) days(fs *flag.FlagSet, args []string) error {
+	n := fs.Int("n", 8, "number of days")
+	name := fs.String("weekday", "", "list only the day of the week, by its name or element (e.g. Firesday or Fire)")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	var only *vanatime.Weekday
+	if *name != "" {
+		w, ok := parseWeekday(*name)
+		if !ok {
+			return c.usageError(fs, "unknown weekday %q", *name)
+		}
+		only = &w
+	}
+
+	var events []eventInfo
+	t := c.clock.Now()
+	for len(events) < *n {
+		if only != nil {
+			t = vanatime.NextDayOfElement(t, only.Element())
+		} else {
+			t = t.Truncate(vanatime.Day).Add(vanatime.Day)
+		}
+		w := t.Weekday()
+		events = append(events, eventInfo{
+			Vana:    t,
+			Earth:   t.Earth(),
+			Name:    w.StringLocale(c.locale),
+			Index:   int(w),
+			Element: w.Element().StringLocale(c.locale),
+		})
+	}
+	return c.printEvents(events)
+}
+
+// parseWeekday parses the name, the element or the number of a day of the
+// week, ignoring case.
+func parseWeekday(s string) (vanatime.Weekday, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return vanatime.Weekday(n), n >= 0 && n < 8
+	}
+	for w := vanatime.Firesday; w <= vanatime.Darksday; w++ {
+		if strings.EqualFold(s, w.String()) || strings.EqualFold(s, w.Element().String()) {
+			return w, true
+		}
+	}
+	return 0, false
+}
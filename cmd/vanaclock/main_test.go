@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+var testNow = vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+
+func runTest(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr, vanatime.NewManualClock(testNow))
+	if code != 0 {
+		t.Logf("stderr: %s", stderr.String())
+	}
+	return stdout.String(), code
+}
+
+func TestNow(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "1313-04-13 21:20:27 Lightsday Waxing Crescent (33%)\n"},
+		{[]string{"-f", "%F %a"}, "1313-04-13 Lgt\n"},
+		{[]string{"now", "-f", "%A", "-l", "ja"}, "光曜日\n"},
+	}
+	for _, tt := range tests {
+		got, code := runTest(t, tt.args...)
+		if code != 0 || got != tt.want {
+			t.Errorf("vanaclock %v = %q, %d; want %q, 0", tt.args, got, code, tt.want)
+		}
+	}
+}
+
+func TestNowJSON(t *testing.T) {
+	out, code := runTest(t, "-json", "-f", "%T")
+	if code != 0 {
+		t.Fatalf("exit status = %d", code)
+	}
+	var info timeInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatal(err)
+	}
+	if !info.Vana.Equal(testNow) || !info.Earth.Equal(testNow.Earth()) {
+		t.Errorf("time = %v, %v; want %v, %v", info.Vana, info.Earth, testNow, testNow.Earth())
+	}
+	if info.Weekday != "Lightsday" || info.Element != "Light" || info.Percent != 33 || info.Formatted != "21:20:27" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	out, code := runTest(t, "convert", "-earth", "2018-11-05T21:58:25+09:00")
+	if want := "1313-04-13 21:20:25 Lightsday Waxing Crescent (33%)\n"; code != 0 || out != want {
+		t.Errorf("convert -earth = %q, %d; want %q", out, code, want)
+	}
+
+	out, code = runTest(t, "convert", "-json", "-vana", "1313-04-13 21:20:25")
+	var info timeInfo
+	if err := json.Unmarshal([]byte(out), &info); code != 0 || err != nil {
+		t.Fatalf("convert -vana: %v, %d", err, code)
+	}
+	if want := time.Date(2018, 11, 5, 12, 58, 25, 0, time.UTC); !info.Earth.Equal(want) {
+		t.Errorf("convert -vana = %v; want %v", info.Earth, want)
+	}
+
+	if _, code := runTest(t, "convert"); code != 2 {
+		t.Errorf("convert without a time: exit status = %d; want 2", code)
+	}
+	if _, code := runTest(t, "convert", "-vana", "tomorrow"); code != 1 {
+		t.Errorf("convert with an invalid time: exit status = %d; want 1", code)
+	}
+}
+
+func TestMoon(t *testing.T) {
+	out, code := runTest(t, "moon", "-json")
+	var info moonInfo
+	if err := json.Unmarshal([]byte(out), &info); code != 0 || err != nil {
+		t.Fatalf("moon: %v, %d", err, code)
+	}
+	m := testNow.Moon()
+	if info.Percent != 33 || info.Age != m.Age() || info.Cycle != m.Cycle() || !info.Waxing {
+		t.Errorf("moon = %+v", info)
+	}
+	if want := vanatime.PhaseEnd(testNow); !info.PhaseEnd.Equal(want) {
+		t.Errorf("phase end = %v; want %v", info.PhaseEnd, want)
+	}
+}
+
+func TestPhases(t *testing.T) {
+	out, code := runTest(t, "phases", "-json", "-n", "2", "-phase", "full moon")
+	var events []eventInfo
+	if err := json.Unmarshal([]byte(out), &events); code != 0 || err != nil {
+		t.Fatalf("phases: %v, %d", err, code)
+	}
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d; want 2", len(events))
+	}
+	for _, e := range events {
+		if e.Name != "Full Moon" || e.Vana.Moon().Phase() != vanatime.FullMoon {
+			t.Errorf("event = %+v; want a full moon", e)
+		}
+	}
+	if d := events[1].Vana.Sub(events[0].Vana); d != vanatime.Duration(vanatime.MoonCycleDays)*vanatime.Day {
+		t.Errorf("full moons %v apart", d)
+	}
+
+	if _, code := runTest(t, "phases", "-phase", "blue moon"); code != 2 {
+		t.Errorf("unknown phase: exit status = %d; want 2", code)
+	}
+}
+
+func TestDays(t *testing.T) {
+	out, code := runTest(t, "days", "-n", "2")
+	want := "1313-04-14 00:00:00  Darksday (Dark)"
+	if code != 0 || !strings.HasPrefix(out, want) {
+		t.Errorf("days = %q; want prefix %q", out, want)
+	}
+
+	out, code = runTest(t, "days", "-json", "-n", "2", "-weekday", "fire")
+	var events []eventInfo
+	if err := json.Unmarshal([]byte(out), &events); code != 0 || err != nil {
+		t.Fatalf("days: %v, %d", err, code)
+	}
+	wants := []vanatime.Time{
+		vanatime.Date(1313, 4, 15, 0, 0, 0, 0),
+		vanatime.Date(1313, 4, 23, 0, 0, 0, 0),
+	}
+	for i, e := range events {
+		if !e.Vana.Equal(wants[i]) || e.Name != "Firesday" || e.Element != "Fire" {
+			t.Errorf("events[%d] = %+v; want Firesday %v", i, e, wants[i])
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	clock := vanatime.NewManualClock(testNow)
+	var stdout, stderr bytes.Buffer
+	done := make(chan int)
+	go func() {
+		done <- run([]string{"watch", "-n", "3"}, &stdout, &stderr, clock)
+	}()
+
+	// advance the clock until the command has drawn three times
+	for {
+		select {
+		case code := <-done:
+			if code != 0 {
+				t.Fatalf("exit status = %d: %s", code, stderr.String())
+			}
+			lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("watch printed %d lines; want 3:\n%s", len(lines), stdout.String())
+			}
+			want := "1313-04-13 21:20 Lightsday (Light) Waxing Crescent 33%; next day in 0d 02:39 (Earth 6m23s)"
+			if !strings.HasPrefix(lines[0], want) {
+				t.Errorf("lines[0] = %q; want prefix %q", lines[0], want)
+			}
+			if !strings.HasPrefix(lines[1], "1313-04-13 21:21 ") {
+				t.Errorf("lines[1] = %q; want the next minute", lines[1])
+			}
+			if strings.Contains(stdout.String(), "\x1b[") {
+				t.Error("watch printed ANSI escape sequences to a non-terminal")
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+			clock.Advance(vanatime.Minute)
+		}
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	c := &cli{locale: "en"}
+	s := c.dashboard(testNow)
+	for _, want := range []string{
+		"███   █   ███ ███", // first row of "21:20"
+		elementColors[vanatime.ElementLight] + "Lightsday",
+		"🌒  Waxing Crescent 33%",
+		"Next week   1d 02:39",
+		"Next phase  2d 02:39 (Earth 2h1m35s)",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("dashboard does not contain %q:\n%s", want, s)
+		}
+	}
+}
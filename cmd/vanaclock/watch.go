@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// ANSI escape sequences of the dashboard.
+const (
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiDim        = "\x1b[2m"
+)
+
+// elementColors are the ANSI colors of the weekday names.
+var elementColors = [...]string{
+	vanatime.ElementFire:      "\x1b[31m", // red
+	vanatime.ElementEarth:     "\x1b[33m", // yellow
+	vanatime.ElementWater:     "\x1b[34m", // blue
+	vanatime.ElementWind:      "\x1b[32m", // green
+	vanatime.ElementIce:       "\x1b[36m", // cyan
+	vanatime.ElementLightning: "\x1b[35m", // magenta
+	vanatime.ElementLight:     "\x1b[97m", // bright white
+	vanatime.ElementDark:      "\x1b[90m", // gray
+}
+
+var moonGlyphs = [...]string{
+	vanatime.NewMoon:         "🌑",
+	vanatime.WaxingCrescent1: "🌒",
+	vanatime.WaxingCrescent2: "🌒",
+	vanatime.FirstQuarter:    "🌓",
+	vanatime.WaxingGibbous1:  "🌔",
+	vanatime.WaxingGibbous2:  "🌔",
+	vanatime.FullMoon:        "🌕",
+	vanatime.WaningGibbous1:  "🌖",
+	vanatime.WaningGibbous2:  "🌖",
+	vanatime.LastQuarter:     "🌗",
+	vanatime.WaningCrescent1: "🌘",
+	vanatime.WaningCrescent2: "🌘",
+}
+
+// bigDigits are the glyphs of the big-digit clock, five rows each.
+var bigDigits = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {"  █", "  █", "  █", "  █", "  █"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// bigText returns s drawn in big digits.
+func bigText(s string) string {
+	var rows [5][]string
+	for _, r := range s {
+		g := bigDigits[r]
+		for i := range rows {
+			rows[i] = append(rows[i], g[i])
+		}
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(strings.Join(row, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// A countdown is the time left until an event.
+type countdown struct {
+	label string
+	at    vanatime.Time
+}
+
+func countdowns(t vanatime.Time) []countdown {
+	return []countdown{
+		{"Next day", t.Truncate(vanatime.Day).Add(vanatime.Day)},
+		{"Next week", t.Truncate(vanatime.Week).Add(vanatime.Week)},
+		{"Next phase", vanatime.PhaseEnd(t)},
+	}
+}
+
+// left formats the Vana'diel and Earth time from t until the countdown.
+func (cd countdown) left(t vanatime.Time) string {
+	d := cd.at.Sub(t)
+	earth := cd.at.Earth().Sub(t.Earth()).Round(time.Second)
+	return fmt.Sprintf("%dd %02d:%02d (Earth %v)",
+		d/vanatime.Day, d%vanatime.Day/vanatime.Hour, d%vanatime.Hour/vanatime.Minute, earth)
+}
+
+// dashboard returns the ANSI dashboard of t.
+func (c *cli) dashboard(t vanatime.Time) string {
+	w := t.Weekday()
+	m := t.Moon()
+
+	var b strings.Builder
+	b.WriteString(ansiClear)
+	b.WriteString(ansiBold + bigText(t.Strftime("%H:%M")) + ansiReset)
+	b.WriteByte('\n')
+	fmt.Fprintf(&b, "%s  %s%s%s%s %s(%s)%s\n",
+		t.Strftime("%Y-%m-%d"),
+		ansiBold, elementColors[w.Element()], w.StringLocale(c.locale), ansiReset,
+		ansiDim, w.Element().StringLocale(c.locale), ansiReset)
+	fmt.Fprintf(&b, "%s  %s %d%%\n", moonGlyphs[m.Phase()], m.Phase().StringLocale(c.locale), m.Percent())
+	fmt.Fprintf(&b, "%sEarth %s%s\n\n", ansiDim, t.Earth().Format(earthLayout), ansiReset)
+	for _, cd := range countdowns(t) {
+		fmt.Fprintf(&b, "%-11s %s\n", cd.label, cd.left(t))
+	}
+	return b.String()
+}
+
+// plain returns t and the countdowns as a line of plain text.
+func (c *cli) plain(t vanatime.Time) string {
+	w := t.Weekday()
+	m := t.Moon()
+	s := fmt.Sprintf("%s %s (%s) %s %d%%",
+		t.Strftime("%Y-%m-%d %H:%M"),
+		w.StringLocale(c.locale), w.Element().StringLocale(c.locale),
+		m.Phase().StringLocale(c.locale), m.Percent())
+	for _, cd := range countdowns(t) {
+		s += "; " + strings.ToLower(cd.label) + " in " + cd.left(t)
+	}
+	return s + "\n"
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (c *cli) watch(fs *flag.FlagSet, args []string) error {
+	plain := fs.Bool("plain", false, "print plain text even to a terminal")
+	count := fs.Int("n", 0, "number of times to draw (default until interrupted)")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	draw := func(t vanatime.Time) {
+		fmt.Fprint(c.stdout, c.plain(t))
+	}
+	switch {
+	case c.json:
+		draw = func(t vanatime.Time) {
+			c.printJSON(c.timeInfo(t))
+		}
+	case !*plain && isTerminal(c.stdout):
+		fmt.Fprint(c.stdout, ansiHideCursor)
+		defer fmt.Fprint(c.stdout, ansiShowCursor)
+		draw = func(t vanatime.Time) {
+			fmt.Fprint(c.stdout, c.dashboard(t))
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	// redraw at every start of a Vana'diel minute
+	ticker := c.clock.NewAlignedTicker(vanatime.Minute)
+	defer ticker.Stop()
+
+	draw(c.clock.Now())
+	for i := 1; *count <= 0 || i < *count; i++ {
+		select {
+		case t := <-ticker.C:
+			draw(t)
+		case <-interrupt:
+			return nil
+		}
+	}
+	return nil
+}
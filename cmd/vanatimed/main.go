@@ -0,0 +1,34 @@
+// Command vanatimed serves the HTTP API of Vana'diel time of the server
+// package.
+//
+// Usage:
+//
+//     vanatimed [-addr host:port]
+//
+// For example:
+//
+//     $ vanatimed -addr localhost:8080 &
+//     $ curl 'http://localhost:8080/upcoming?event=fullmoon&n=5'
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pasela/go-vanatime/server"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	flag.Parse()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.NewHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,103 @@
+// Package conquest provides the schedule of the Conquest tally.
+//
+// Conquest results are tallied once per Vana'diel week, at 00:00 of every
+// Firesday (every 8 Vana'diel days, 7 hours 40 minutes 48 seconds of the
+// Earth). Conquest weeks are numbered from the week starting at
+// C.E. 0001-01-01 00:00:00, which is week 0.
+package conquest
+
+import (
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// Period is the interval between two Conquest tallies.
+const Period = vanatime.Week
+
+// WeekIndex returns the index of the Conquest week in which t occurs.
+func WeekIndex(t vanatime.Time) int64 {
+	return t.Truncate(Period).Int64() / int64(Period)
+}
+
+// WeekStart returns the time at which the Conquest week with the given
+// index starts, that is, the tally which opens it.
+func WeekStart(index int64) vanatime.Time {
+	return vanatime.FromInt64(index * int64(Period))
+}
+
+// Week returns the start and end of the Conquest week in which t occurs.
+// The end is exclusive and is the time of the next tally.
+func Week(t vanatime.Time) (start, end vanatime.Time) {
+	start = WeekStart(WeekIndex(t))
+	return start, start.Add(Period)
+}
+
+// NextTally returns the time of the first tally after t.
+func NextTally(t vanatime.Time) vanatime.Time {
+	_, end := Week(t)
+	return end
+}
+
+// Until returns the duration from t until the next tally.
+func Until(t vanatime.Time) vanatime.Duration {
+	return NextTally(t).Sub(t)
+}
+
+// A Tally is an occurrence of the Conquest tally.
+type Tally struct {
+	Week int64         // index of the week opened by the tally
+	Time vanatime.Time // time of the tally
+}
+
+// Earth returns the Earth time of the tally.
+func (t Tally) Earth() time.Time {
+	return t.Time.Earth()
+}
+
+// NextTallies returns the next n tallies after t, or nil if n <= 0.
+func NextTallies(t vanatime.Time, n int) []Tally {
+	if n <= 0 {
+		return nil
+	}
+	tallies := make([]Tally, 0, n)
+	week := WeekIndex(t) + 1
+	for i := 0; i < n; i++ {
+		tallies = append(tallies, Tally{
+			Week: week,
+			Time: WeekStart(week),
+		})
+		week++
+	}
+	return tallies
+}
+
+// A Ticker holds a channel that delivers the time of each Conquest tally.
+type Ticker struct {
+	C <-chan vanatime.Time
+
+	ticker *vanatime.Ticker
+}
+
+// NewTicker returns a new Ticker which sends the time of each tally on its
+// channel as it happens. Like the ticker of vanatime.NewAlignedTicker, it
+// sends every tally in order, waiting for slow receivers. Stop the ticker to
+// release associated resources.
+func NewTicker() *Ticker {
+	return NewTickerClock(vanatime.SystemClock)
+}
+
+// NewTickerClock is like NewTicker but uses the given clock.
+func NewTickerClock(clock vanatime.Clock) *Ticker {
+	// the tallies are the multiples of Period since the zero time
+	ticker := clock.NewAlignedTicker(Period)
+	return &Ticker{
+		C:      ticker.C,
+		ticker: ticker,
+	}
+}
+
+// Stop turns off a ticker. After Stop, no more tallies will be sent.
+func (t *Ticker) Stop() {
+	t.ticker.Stop()
+}
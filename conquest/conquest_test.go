@@ -0,0 +1,107 @@
+package conquest_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/conquest"
+)
+
+func TestWeek(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	start, end := conquest.Week(vt)
+
+	wantStart := vt.Truncate(vanatime.Week)
+	if !start.Equal(wantStart) {
+		t.Errorf(`want "%v", but "%v"`, wantStart, start)
+	}
+	if !end.Equal(wantStart.Add(vanatime.Week)) {
+		t.Errorf(`want "%v", but "%v"`, wantStart.Add(vanatime.Week), end)
+	}
+	if start.Weekday() != vanatime.Firesday || start.Hour() != 0 || start.Minute() != 0 {
+		t.Errorf(`week starts at "%v"`, start)
+	}
+	if start.After(vt) || !end.After(vt) {
+		t.Errorf(`"%v" is not in ["%v", "%v")`, vt, start, end)
+	}
+
+	if got, want := conquest.WeekStart(conquest.WeekIndex(vt)), start; !got.Equal(want) {
+		t.Errorf(`want "%v", but "%v"`, want, got)
+	}
+	if got := conquest.WeekIndex(vanatime.Date(1, 1, 1, 0, 0, 0, 0)); got != 0 {
+		t.Errorf("want 0, but %d", got)
+	}
+	if got := conquest.WeekIndex(vanatime.Date(1, 1, 1, 0, 0, 0, 0).Add(-1)); got != -1 {
+		t.Errorf("want -1, but %d", got)
+	}
+}
+
+func TestNextTally(t *testing.T) {
+	patterns := []struct {
+		T    vanatime.Time
+		Want vanatime.Time
+	}{
+		// 1313-04-13 is Lightsday, so the next Firesday is 1313-04-15
+		{vanatime.Date(1313, 4, 13, 21, 20, 27, 0), vanatime.Date(1313, 4, 15, 0, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 14, 23, 59, 59, 999999), vanatime.Date(1313, 4, 15, 0, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 15, 0, 0, 0, 0), vanatime.Date(1313, 4, 23, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got := conquest.NextTally(pattern.T)
+		if !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+		if d := conquest.Until(pattern.T); d != pattern.Want.Sub(pattern.T) {
+			t.Errorf(`[%d]: want %v, but %v`, i, pattern.Want.Sub(pattern.T), d)
+		}
+	}
+}
+
+func TestNextTallies(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	tallies := conquest.NextTallies(vt, 3)
+	if len(tallies) != 3 {
+		t.Fatalf("want 3 tallies, but %d", len(tallies))
+	}
+
+	want := vanatime.Date(1313, 4, 15, 0, 0, 0, 0)
+	for i, tally := range tallies {
+		if !tally.Time.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, tally.Time)
+		}
+		if tally.Week != conquest.WeekIndex(want) {
+			t.Errorf(`[%d]: want week %d, but %d`, i, conquest.WeekIndex(want), tally.Week)
+		}
+		if !tally.Earth().Equal(want.Earth()) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want.Earth(), tally.Earth())
+		}
+		want = want.Add(conquest.Period)
+	}
+
+	if tallies := conquest.NextTallies(vt, 0); tallies != nil {
+		t.Errorf("want no tallies, but %v", tallies)
+	}
+	if tallies := conquest.NextTallies(vt, -1); tallies != nil {
+		t.Errorf("want no tallies, but %v", tallies)
+	}
+}
+
+func TestTicker(t *testing.T) {
+	clock := vanatime.NewManualClock(vanatime.Date(1313, 4, 13, 21, 20, 27, 0))
+	ticker := conquest.NewTickerClock(clock)
+	defer ticker.Stop()
+
+	want := vanatime.Date(1313, 4, 15, 0, 0, 0, 0)
+	for i := 0; i < 3; i++ {
+		for clock.Pending() == 0 {
+			vanatime.Sleep(vanatime.Millisecond)
+		}
+		clock.Set(want)
+		got := <-ticker.C
+		if !got.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+		want = want.Add(conquest.Period)
+	}
+}
@@ -2,7 +2,7 @@ package vanatime
 
 import (
 	"errors"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -30,16 +30,172 @@ const (
 // signed sequence of decimal numbers, each with optional fraction and a unit
 // suffix, such as "300ms", "-1.5h" or "2h45m". Valid time units are
 // "us" (or "µs"), "ms", "s", "m", "h".
+//
+// It accepts every duration formatted by Duration.String, including those
+// longer than the range of time.Duration.
 func ParseDuration(s string) (Duration, error) {
-	if strings.Contains(s, "ns") {
-		return 0, errors.New("vanatime: unknown unit ns in duration " + s)
+	// [-+]?([0-9]*(\.[0-9]*)?[a-z]+)+
+	orig := s
+	var d uint64
+	neg := false
+
+	// Consume [-+]?
+	if s != "" {
+		c := s[0]
+		if c == '-' || c == '+' {
+			neg = c == '-'
+			s = s[1:]
+		}
+	}
+	// Special case: if all that is left is "0", this is zero.
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
 	}
+	for s != "" {
+		var (
+			v, f  uint64      // integers before, after decimal point
+			scale float64 = 1 // value = v + f/scale
+		)
+
+		var err error
+
+		// The next character must be [0-9.]
+		if !(s[0] == '.' || '0' <= s[0] && s[0] <= '9') {
+			return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+		}
+		// Consume [0-9]*
+		pl := len(s)
+		v, s, err = leadingInt(s)
+		if err != nil {
+			return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+		}
+		pre := pl != len(s) // whether we consumed anything before a period
+
+		// Consume (\.[0-9]*)?
+		post := false
+		if s != "" && s[0] == '.' {
+			s = s[1:]
+			pl := len(s)
+			f, scale, s = leadingFraction(s)
+			post = pl != len(s)
+		}
+		if !pre && !post {
+			// no digits (e.g. ".s" or "-.s")
+			return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+		}
 
-	d, err := time.ParseDuration(s)
-	if err != nil {
-		return 0, err
+		// Consume unit.
+		i := 0
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c == '.' || '0' <= c && c <= '9' {
+				break
+			}
+		}
+		if i == 0 {
+			return 0, errors.New("vanatime: missing unit in duration " + strconv.Quote(orig))
+		}
+		u := s[:i]
+		s = s[i:]
+		unit, ok := unitMap[u]
+		if !ok {
+			return 0, errors.New("vanatime: unknown unit " + strconv.Quote(u) + " in duration " + strconv.Quote(orig))
+		}
+		if v > 1<<63/unit {
+			// overflow
+			return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+		}
+		v *= unit
+		if f > 0 {
+			// float64 is needed to be microsecond accurate for fractions of hours.
+			// v >= 0 && (f*unit/scale) <= 3.6e+12 (µs/h, h is the largest unit)
+			v += uint64(float64(f) * (float64(unit) / scale))
+			if v > 1<<63 {
+				// overflow
+				return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+			}
+		}
+		d += v
+		if d > 1<<63 {
+			return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+		}
 	}
-	return Duration(d / 1000), nil
+	if neg {
+		return -Duration(d), nil
+	}
+	if d > 1<<63-1 {
+		return 0, errors.New("vanatime: invalid duration " + strconv.Quote(orig))
+	}
+	return Duration(d), nil
+}
+
+var unitMap = map[string]uint64{
+	"us": uint64(Microsecond),
+	"µs": uint64(Microsecond), // U+00B5 = micro symbol
+	"μs": uint64(Microsecond), // U+03BC = Greek letter mu
+	"ms": uint64(Millisecond),
+	"s":  uint64(Second),
+	"m":  uint64(Minute),
+	"h":  uint64(Hour),
+}
+
+var errLeadingInt = errors.New("vanatime: bad [0-9]*") // never printed
+
+// leadingInt consumes the leading [0-9]* from s.
+// from https://golang.org/src/time/format.go
+func leadingInt(s string) (x uint64, rem string, err error) {
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			break
+		}
+		if x > 1<<63/10 {
+			// overflow
+			return 0, "", errLeadingInt
+		}
+		x = x*10 + uint64(c) - '0'
+		if x > 1<<63 {
+			// overflow
+			return 0, "", errLeadingInt
+		}
+	}
+	return x, s[i:], nil
+}
+
+// leadingFraction consumes the leading [0-9]* from s.
+// It is used only for fractions, so does not return an error on overflow,
+// it just stops accumulating precision.
+// from https://golang.org/src/time/format.go
+func leadingFraction(s string) (x uint64, scale float64, rem string) {
+	i := 0
+	scale = 1
+	overflow := false
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			break
+		}
+		if overflow {
+			continue
+		}
+		if x > (1<<63-1)/10 {
+			// It's possible for overflow to give a positive number, so take care.
+			overflow = true
+			continue
+		}
+		y := x*10 + uint64(c) - '0'
+		if y > 1<<63 {
+			overflow = true
+			continue
+		}
+		x = y
+		scale *= 10
+	}
+	return x, scale, s[i:]
 }
 
 // Microseconds returns the duration as an integer microsecond count.
@@ -83,8 +239,27 @@ func (d Duration) Truncate(m Duration) Duration {
 // in a Duration, Round returns the maximum (or minimum) duration.
 // If m <= 0, Round returns d unchanged.
 func (d Duration) Round(m Duration) Duration {
-	t := time.Duration(d * 1000).Round(time.Duration(m * 1000))
-	return Duration(t / 1000)
+	if m <= 0 {
+		return d
+	}
+	r := d % m
+	if d < 0 {
+		r = -r
+		if lessThanHalf(r, m) {
+			return d + r
+		}
+		if d1 := d - m + r; d1 < d {
+			return d1
+		}
+		return minDuration // overflow
+	}
+	if lessThanHalf(r, m) {
+		return d - r
+	}
+	if d1 := d + m - r; d1 > d {
+		return d1
+	}
+	return maxDuration // overflow
 }
 
 // String returns a string representing the duration in the form "72h3m0.5s".
@@ -92,7 +267,117 @@ func (d Duration) Round(m Duration) Duration {
 // second format use a smaller unit (milli-, microseconds) to ensure that the
 // leading digit is non-zero. The zero duration formats as 0s.
 func (d Duration) String() string {
-	return time.Duration(d * 1000).String()
+	// Largest value is "-2562047788h0m54.775808s".
+	// It does not go through time.Duration, whose range is 1000 times
+	// smaller.
+	var buf [32]byte
+	w := len(buf)
+
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(Second) {
+		// Special case: if duration is smaller than a second,
+		// use smaller units, like 1.2ms
+		var prec int
+		w--
+		buf[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			return "0s"
+		case u < uint64(Millisecond):
+			// print microseconds
+			prec = 0
+			// U+00B5 'µ' micro sign == 0xC2 0xB5
+			w-- // Need room for two bytes.
+			copy(buf[w:], "µ")
+		default:
+			// print milliseconds
+			prec = 3
+			buf[w] = 'm'
+		}
+		w, u = fmtFrac(buf[:w], u, prec)
+		w = fmtInt(buf[:w], u)
+	} else {
+		w--
+		buf[w] = 's'
+
+		w, u = fmtFrac(buf[:w], u, 6)
+
+		// u is now integer seconds
+		w = fmtInt(buf[:w], u%60)
+		u /= 60
+
+		// u is now integer minutes
+		if u > 0 {
+			w--
+			buf[w] = 'm'
+			w = fmtInt(buf[:w], u%60)
+			u /= 60
+
+			// u is now integer hours
+			// Stop at hours because days can be different lengths.
+			if u > 0 {
+				w--
+				buf[w] = 'h'
+				w = fmtInt(buf[:w], u)
+			}
+		}
+	}
+
+	if neg {
+		w--
+		buf[w] = '-'
+	}
+
+	return string(buf[w:])
+}
+
+// fmtFrac formats the fraction of v/10**prec (e.g., ".12345") into the
+// tail of buf, omitting trailing zeros. It omits the decimal
+// point too when the fraction is 0. It returns the index where the
+// output bytes begin and the value v/10**prec.
+// from https://golang.org/src/time/time.go
+func fmtFrac(buf []byte, v uint64, prec int) (nw int, nv uint64) {
+	// Omit trailing zeros up to and including decimal point.
+	w := len(buf)
+	print := false
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		print = print || digit != 0
+		if print {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if print {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// fmtInt formats v into the tail of buf.
+// It returns the index where the output begins.
+// from https://golang.org/src/time/time.go
+func fmtInt(buf []byte, v uint64) int {
+	w := len(buf)
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for v > 0 {
+			w--
+			buf[w] = byte(v%10) + '0'
+			v /= 10
+		}
+	}
+	return w
 }
 
 // Since returns the time elapsed since t. It is shorthand for time.Now().Sub(t).
@@ -80,6 +80,19 @@ func TestDurationRound(t *testing.T) {
 	}
 }
 
+func TestDurationRoundLarge(t *testing.T) {
+	d := 1312*vanatime.Year + 13*vanatime.Hour
+	if got, want := d.Round(vanatime.Day), 1312*vanatime.Year+vanatime.Day; got != want {
+		t.Errorf("want %v, but %v", want, got)
+	}
+	if got, want := (-d).Round(vanatime.Day), -1312*vanatime.Year-vanatime.Day; got != want {
+		t.Errorf("want %v, but %v", want, got)
+	}
+	if got, want := vanatime.Duration(1<<63-1).Round(1<<62), vanatime.Duration(1<<63-1); got != want {
+		t.Errorf("want %v, but %v", want, got)
+	}
+}
+
 func TestDurationString(t *testing.T) {
 	cases := []struct {
 		D    vanatime.Duration
@@ -95,6 +108,10 @@ func TestDurationString(t *testing.T) {
 		{34*vanatime.Second + 56*vanatime.Microsecond, "34.000056s"},
 		{3 * vanatime.Millisecond, "3ms"},
 		{3 * vanatime.Microsecond, "3µs"},
+		{-3*vanatime.Hour - 500*vanatime.Millisecond, "-3h0m0.5s"},
+		{1312 * vanatime.Year, "11335680h0m0s"},
+		{1<<63 - 1, "2562047788h0m54.775807s"},
+		{-1 << 63, "-2562047788h0m54.775808s"},
 	}
 	for i, c := range cases {
 		got := c.D.String()
@@ -120,6 +137,13 @@ func TestParseDuration(t *testing.T) {
 		{"34.000056s", 34*vanatime.Second + 56*vanatime.Microsecond},
 		{"3ms", 3 * vanatime.Millisecond},
 		{"3µs", 3 * vanatime.Microsecond},
+		{"3us", 3 * vanatime.Microsecond},
+		{"-1.5h", -90 * vanatime.Minute},
+		{"+0.5ms", 500 * vanatime.Microsecond},
+		{"0", 0},
+		{"11335680h", 1312 * vanatime.Year},
+		{"2562047788h0m54.775807s", 1<<63 - 1},
+		{"-2562047788h0m54.775808s", -1 << 63},
 	}
 	for i, c := range cases {
 		got, err := vanatime.ParseDuration(c.S)
@@ -136,6 +160,13 @@ func TestParseDuration(t *testing.T) {
 func TestParseDurationError(t *testing.T) {
 	cases := []string{
 		"1ns",
+		"",
+		"-",
+		"1",
+		".s",
+		"1x",
+		"2562047788h0m54.775808s",
+		"9999999999999h",
 	}
 	for i, c := range cases {
 		_, err := vanatime.ParseDuration(c)
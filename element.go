@@ -0,0 +1,87 @@
+package vanatime
+
+// An Element specifies one of the eight elements of Vana'diel
+// (ElementFire = 0, ...).
+// Each day of the week corresponds to an element.
+//
+//     Fire > Ice > Wind > Earth > Lightning > Water > Fire
+//     Light <> Dark
+type Element int
+
+const (
+	ElementFire Element = iota
+	ElementEarth
+	ElementWater
+	ElementWind
+	ElementIce
+	ElementLightning
+	ElementLight
+	ElementDark
+)
+
+var defaultElementNames = [...]string{
+	"Fire",
+	"Earth",
+	"Water",
+	"Wind",
+	"Ice",
+	"Lightning",
+	"Light",
+	"Dark",
+}
+
+// the element each element is strong against
+var strongAgainst = [...]Element{
+	ElementFire:      ElementIce,
+	ElementEarth:     ElementLightning,
+	ElementWater:     ElementFire,
+	ElementWind:      ElementEarth,
+	ElementIce:       ElementWind,
+	ElementLightning: ElementWater,
+	ElementLight:     ElementDark,
+	ElementDark:      ElementLight,
+}
+
+// String returns the English name of the element ("Fire", "Earth", ...).
+func (e Element) String() string {
+	return defaultElementNames[e]
+}
+
+// StringLocale returns the name of the element by specified locale.
+func (e Element) StringLocale(locale string) string {
+	return lookupLocale(locale).Elements[e]
+}
+
+// StrongAgainst returns the element which e is strong against.
+func (e Element) StrongAgainst() Element {
+	return strongAgainst[e]
+}
+
+// WeakAgainst returns the element which e is weak against, that is, the
+// element which is strong against e.
+func (e Element) WeakAgainst() Element {
+	for i, s := range strongAgainst {
+		if s == e {
+			return Element(i)
+		}
+	}
+	panic("vanatime: invalid element")
+}
+
+// Weekday returns the day of the week corresponding to e.
+func (e Element) Weekday() Weekday {
+	return Weekday(e)
+}
+
+// Element returns the element corresponding to the day of the week.
+func (w Weekday) Element() Element {
+	return Element(w)
+}
+
+// NextDayOfElement returns the start of the first day after t whose
+// element is e.
+func NextDayOfElement(t Time, e Element) Time {
+	day := t.Truncate(Day).Add(Day)
+	n := (int(e) - int(day.Weekday()) + 8) % 8
+	return day.Add(Duration(n) * Day)
+}
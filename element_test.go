@@ -0,0 +1,94 @@
+package vanatime_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func TestWeekdayElement(t *testing.T) {
+	patterns := []struct {
+		W    vanatime.Weekday
+		Want vanatime.Element
+	}{
+		{vanatime.Firesday, vanatime.ElementFire},
+		{vanatime.Earthsday, vanatime.ElementEarth},
+		{vanatime.Watersday, vanatime.ElementWater},
+		{vanatime.Windsday, vanatime.ElementWind},
+		{vanatime.Iceday, vanatime.ElementIce},
+		{vanatime.Lightningday, vanatime.ElementLightning},
+		{vanatime.Lightsday, vanatime.ElementLight},
+		{vanatime.Darksday, vanatime.ElementDark},
+	}
+
+	for i, pattern := range patterns {
+		if got := pattern.W.Element(); got != pattern.Want {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+		if got := pattern.Want.Weekday(); got != pattern.W {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.W, got)
+		}
+	}
+}
+
+func TestElementString(t *testing.T) {
+	if got := vanatime.ElementLightning.String(); got != "Lightning" {
+		t.Errorf(`want "Lightning", but "%s"`, got)
+	}
+	if got := vanatime.ElementLightning.StringLocale("ja"); got != "雷" {
+		t.Errorf(`want "雷", but "%s"`, got)
+	}
+	if got := vanatime.ElementLightning.StringLocale("en-US"); got != "Lightning" {
+		t.Errorf(`want "Lightning", but "%s"`, got)
+	}
+}
+
+func TestElementAffinity(t *testing.T) {
+	patterns := []struct {
+		E      vanatime.Element
+		Strong vanatime.Element
+		Weak   vanatime.Element
+	}{
+		{vanatime.ElementFire, vanatime.ElementIce, vanatime.ElementWater},
+		{vanatime.ElementIce, vanatime.ElementWind, vanatime.ElementFire},
+		{vanatime.ElementWind, vanatime.ElementEarth, vanatime.ElementIce},
+		{vanatime.ElementEarth, vanatime.ElementLightning, vanatime.ElementWind},
+		{vanatime.ElementLightning, vanatime.ElementWater, vanatime.ElementEarth},
+		{vanatime.ElementWater, vanatime.ElementFire, vanatime.ElementLightning},
+		{vanatime.ElementLight, vanatime.ElementDark, vanatime.ElementDark},
+		{vanatime.ElementDark, vanatime.ElementLight, vanatime.ElementLight},
+	}
+
+	for i, pattern := range patterns {
+		if got := pattern.E.StrongAgainst(); got != pattern.Strong {
+			t.Errorf(`[%d]: %v: want "%v", but "%v"`, i, pattern.E, pattern.Strong, got)
+		}
+		if got := pattern.E.WeakAgainst(); got != pattern.Weak {
+			t.Errorf(`[%d]: %v: want "%v", but "%v"`, i, pattern.E, pattern.Weak, got)
+		}
+	}
+}
+
+func TestNextDayOfElement(t *testing.T) {
+	// 1313-04-13 is Lightsday
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	patterns := []struct {
+		E    vanatime.Element
+		Want vanatime.Time
+	}{
+		{vanatime.ElementDark, vanatime.Date(1313, 4, 14, 0, 0, 0, 0)},
+		{vanatime.ElementFire, vanatime.Date(1313, 4, 15, 0, 0, 0, 0)},
+		{vanatime.ElementLightning, vanatime.Date(1313, 4, 20, 0, 0, 0, 0)},
+		{vanatime.ElementLight, vanatime.Date(1313, 4, 21, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got := vanatime.NextDayOfElement(vt, pattern.E)
+		if !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+		if got.Weekday().Element() != pattern.E {
+			t.Errorf(`[%d]: want %v, but %v`, i, pattern.E, got.Weekday().Element())
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package vanatime
+
+import (
+	"strconv"
+	"strings"
+)
+
+// These are predefined layouts for use in Time.Format and Parse.
+// The reference time used in these layouts is the specific time stamp:
+//
+//     C.E. 0001-02-03 04:05:06 Firesday
+//
+// whose moon is a Full Moon of 95%. That value is recorded as the constant
+// named Layout, listed below. The layout elements are:
+//
+//     Year:         "0001"
+//     Month:        "2", "02"
+//     Day:          "3", "03", "_3"
+//     Day of year:  "033"
+//     Hour:         "4", "04" (24-hour clock)
+//     Minute:       "5", "05"
+//     Second:       "6", "06"
+//     Weekday:      "Firesday"
+//     Moon phase:   "Full Moon"
+//     Moon percent: "95"
+//
+// A decimal point followed by one or more zeros represents a fractional
+// second, printed to the given number of decimal places. A decimal point
+// followed by one or more nines represents a fractional second, printed to
+// the given number of decimal places, with trailing zeros removed.
+// Vana'diel time has microsecond precision, so at most 6 digits are
+// significant.
+const (
+	Layout        = "0001-02-03 04:05:06 Firesday Full Moon (95%)"
+	DateTime      = "0001-02-03 04:05:06"
+	DateTimeMicro = "0001-02-03 04:05:06.000000"
+	DateOnly      = "0001-02-03"
+	TimeOnly      = "04:05:06"
+)
+
+const (
+	_              = iota
+	stdYear            // "0001"
+	stdMonth           // "2"
+	stdZeroMonth       // "02"
+	stdDay             // "3"
+	stdUnderDay        // "_3"
+	stdZeroDay         // "03"
+	stdZeroYearDay     // "033"
+	stdHour            // "4"
+	stdZeroHour        // "04"
+	stdMinute          // "5"
+	stdZeroMinute      // "05"
+	stdSecond          // "6"
+	stdZeroSecond      // "06"
+	stdWeekday         // "Firesday"
+	stdMoonPhase       // "Full Moon"
+	stdMoonPercent     // "95"
+	stdFracSecond0     // ".0", ".00", ... , trailing zeros included
+	stdFracSecond9     // ".9", ".99", ..., trailing zeros omitted
+	stdArgShift    = 8 // extra argument in high bits, above low stdArgShift
+	stdMask        = 1<<stdArgShift - 1
+)
+
+var stdTokens = []struct {
+	token string
+	std   int
+}{
+	// longer tokens first
+	{"Full Moon", stdMoonPhase},
+	{"Firesday", stdWeekday},
+	{"0001", stdYear},
+	{"033", stdZeroYearDay},
+	{"02", stdZeroMonth},
+	{"03", stdZeroDay},
+	{"04", stdZeroHour},
+	{"05", stdZeroMinute},
+	{"06", stdZeroSecond},
+	{"_3", stdUnderDay},
+	{"95", stdMoonPercent},
+	{"2", stdMonth},
+	{"3", stdDay},
+	{"4", stdHour},
+	{"5", stdMinute},
+	{"6", stdSecond},
+}
+
+// nextStdChunk finds the first occurrence of a std string in
+// layout and returns the text before, the std string, and the text after.
+func nextStdChunk(layout string) (prefix string, std int, suffix string) {
+	for i := 0; i < len(layout); i++ {
+		if c := layout[i]; (c == '.' || c == ',') && i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+			ch := layout[i+1]
+			j := i + 1
+			for j < len(layout) && layout[j] == ch {
+				j++
+			}
+			// String of digits must end here - only fractional second if all digits match.
+			if !isDigit(layout, j) {
+				std := stdFracSecond0
+				if ch == '9' {
+					std = stdFracSecond9
+				}
+				return layout[0:i], std | (j-(i+1))<<stdArgShift, layout[j:]
+			}
+		}
+		for _, tok := range stdTokens {
+			if strings.HasPrefix(layout[i:], tok.token) {
+				return layout[0:i], tok.std, layout[i+len(tok.token):]
+			}
+		}
+	}
+	return layout, 0, ""
+}
+
+func isDigit(s string, i int) bool {
+	if len(s) <= i {
+		return false
+	}
+	c := s[i]
+	return '0' <= c && c <= '9'
+}
+
+// appendInt appends the decimal form of x to b, padding it with zeros to
+// at least width digits.
+func appendInt(b []byte, x int, width int) []byte {
+	if x < 0 {
+		b = append(b, '-')
+		x = -x
+	}
+	s := strconv.Itoa(x)
+	for i := len(s); i < width; i++ {
+		b = append(b, '0')
+	}
+	return append(b, s...)
+}
+
+// formatFrac appends the fractional second usec to b, using n digits and
+// dropping trailing zeros if trim is set.
+func formatFrac(b []byte, usec int, n int, trim bool) []byte {
+	digits := strconv.Itoa(usec)
+	digits = strings.Repeat("0", 6-len(digits)) + digits
+	if n > 6 {
+		digits += strings.Repeat("0", n-6)
+	} else {
+		digits = digits[:n]
+	}
+	if trim {
+		digits = strings.TrimRight(digits, "0")
+		if digits == "" {
+			return b
+		}
+	}
+	b = append(b, '.')
+	return append(b, digits...)
+}
+
+// Format returns a textual representation of the time value formatted
+// according to layout, which defines the format by showing how the reference
+// time, defined to be
+//
+//     C.E. 0001-02-03 04:05:06 Firesday Full Moon (95%)
+//
+// would be displayed if it were the value; it serves as an example of the
+// desired output. The same display rules will then be applied to the time
+// value.
+//
+// Predefined layouts DateTime, DateOnly, TimeOnly and others are defined in
+// this package. See the documentation for Layout for the list of elements.
+func (t Time) Format(layout string) string {
+	year, mon, day, yday := t.Date()
+	hour, min, sec := t.Clock()
+	usec := t.Microsecond()
+
+	var b []byte
+	for layout != "" {
+		prefix, std, suffix := nextStdChunk(layout)
+		if prefix != "" {
+			b = append(b, prefix...)
+		}
+		if std == 0 {
+			break
+		}
+		layout = suffix
+
+		switch std & stdMask {
+		case stdYear:
+			b = appendInt(b, year, 4)
+		case stdMonth:
+			b = appendInt(b, mon, 0)
+		case stdZeroMonth:
+			b = appendInt(b, mon, 2)
+		case stdDay:
+			b = appendInt(b, day, 0)
+		case stdUnderDay:
+			if day < 10 {
+				b = append(b, ' ')
+			}
+			b = appendInt(b, day, 0)
+		case stdZeroDay:
+			b = appendInt(b, day, 2)
+		case stdZeroYearDay:
+			b = appendInt(b, yday, 3)
+		case stdHour:
+			b = appendInt(b, hour, 0)
+		case stdZeroHour:
+			b = appendInt(b, hour, 2)
+		case stdMinute:
+			b = appendInt(b, min, 0)
+		case stdZeroMinute:
+			b = appendInt(b, min, 2)
+		case stdSecond:
+			b = appendInt(b, sec, 0)
+		case stdZeroSecond:
+			b = appendInt(b, sec, 2)
+		case stdWeekday:
+			b = append(b, t.Weekday().String()...)
+		case stdMoonPhase:
+			b = append(b, t.Moon().Phase().String()...)
+		case stdMoonPercent:
+			b = appendInt(b, t.Moon().Percent(), 0)
+		case stdFracSecond0, stdFracSecond9:
+			b = formatFrac(b, usec, std>>stdArgShift, std&stdMask == stdFracSecond9)
+		}
+	}
+	return string(b)
+}
+
+// getnum parses a decimal number of at least min and at most max digits
+// from the beginning of s.
+func getnum(s string, min, max int) (int, string, bool) {
+	n := 0
+	for n < len(s) && (max <= 0 || n < max) && isDigit(s, n) {
+		n++
+	}
+	if n == 0 || n < min {
+		return 0, s, false
+	}
+	x, err := strconv.Atoi(s[:n])
+	if err != nil {
+		return 0, s, false
+	}
+	return x, s[n:], true
+}
+
+// lookup matches the longest name in names at the beginning of s, ignoring
+// case.
+func lookup(names []string, s string) (int, string, bool) {
+	found, length := -1, 0
+	for i, name := range names {
+		if len(name) > length && len(s) >= len(name) && strings.EqualFold(s[:len(name)], name) {
+			found, length = i, len(name)
+		}
+	}
+	if found < 0 {
+		return -1, s, false
+	}
+	return found, s[length:], true
+}
+
+// parseFrac parses the digits of a fractional second and returns it as
+// microseconds.
+func parseFrac(digits string) int {
+	if len(digits) > 6 {
+		digits = digits[:6]
+	}
+	digits += strings.Repeat("0", 6-len(digits))
+	usec, _ := strconv.Atoi(digits)
+	return usec
+}
+
+// Parse parses a formatted string and returns the time value it represents.
+// See the documentation for the constant called Layout to see how to
+// represent the format. The second argument must be parseable using the
+// format string (layout) provided as the first argument.
+//
+// Elements omitted from the layout are assumed to be the earliest possible
+// value (year 1, month 1, day 1, 00:00:00). The weekday, moon phase and moon
+// percent are checked for syntax but are otherwise ignored.
+//
+// When parsing a time with a seconds element but no fractional second in
+// the layout, a fractional second in the value is accepted after the
+// seconds field.
+//
+// The returned error is a *ParseError describing the offending layout
+// element and its position in the value.
+func Parse(layout, value string) (Time, error) {
+	alayout, avalue := layout, value
+	year, mon, day, yday := 1, -1, -1, -1
+	var hour, min, sec, usec int
+	ydayOffset := 0 // offset of the day of year, to report a mismatch
+
+	for {
+		var ok bool
+		prefix, std, suffix := nextStdChunk(layout)
+		stdstr := layout[len(prefix) : len(layout)-len(suffix)]
+		value, ok = skip(value, prefix)
+		if !ok {
+			return Time{}, &ParseError{alayout, avalue, "", len(avalue) - len(value), "expected " + strconv.Quote(prefix)}
+		}
+		if std == 0 {
+			if len(value) != 0 {
+				return Time{}, &ParseError{alayout, avalue, "", len(avalue) - len(value), "extra text " + strconv.Quote(value)}
+			}
+			break
+		}
+		layout = suffix
+
+		offset := len(avalue) - len(value)
+		ok = false
+		rangeErr := ""
+		switch std & stdMask {
+		case stdYear:
+			neg := false
+			if len(value) > 0 && (value[0] == '-' || value[0] == '+') {
+				neg = value[0] == '-'
+				value = value[1:]
+			}
+			// A year immediately followed by another number has exactly
+			// four digits.
+			max := 0
+			if p, std, _ := nextStdChunk(layout); p == "" && std != 0 {
+				max = 4
+			}
+			year, value, ok = getnum(value, 4, max)
+			if neg {
+				year = -year
+			}
+		case stdMonth, stdZeroMonth:
+			mon, value, ok = getnum(value, stdDigits(std), 2)
+			if ok && (mon < 1 || mon > 12) {
+				rangeErr = "month"
+			}
+		case stdDay, stdUnderDay, stdZeroDay:
+			if std == stdUnderDay && len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+			day, value, ok = getnum(value, stdDigits(std), 2)
+			if ok && (day < 1 || day > 30) {
+				rangeErr = "day"
+			}
+		case stdZeroYearDay:
+			yday, value, ok = getnum(value, 3, 3)
+			ydayOffset = offset
+			if ok && (yday < 1 || yday > 360) {
+				rangeErr = "day of year"
+			}
+		case stdHour, stdZeroHour:
+			hour, value, ok = getnum(value, stdDigits(std), 2)
+			if ok && (hour < 0 || hour > 23) {
+				rangeErr = "hour"
+			}
+		case stdMinute, stdZeroMinute:
+			min, value, ok = getnum(value, stdDigits(std), 2)
+			if ok && (min < 0 || min > 59) {
+				rangeErr = "minute"
+			}
+		case stdSecond, stdZeroSecond:
+			sec, value, ok = getnum(value, stdDigits(std), 2)
+			if ok && (sec < 0 || sec > 59) {
+				rangeErr = "second"
+				break
+			}
+			// Special case: do we have a fractional second but no
+			// fractional second in the format?
+			if ok && len(value) >= 2 && (value[0] == '.' || value[0] == ',') && isDigit(value, 1) {
+				_, std, _ = nextStdChunk(layout)
+				std &= stdMask
+				if std == stdFracSecond0 || std == stdFracSecond9 {
+					// Fractional second in the layout; proceed normally
+					break
+				}
+				n := 1
+				for isDigit(value, n) {
+					n++
+				}
+				usec = parseFrac(value[1:n])
+				value = value[n:]
+			}
+		case stdWeekday:
+			_, value, ok = lookup(defaultDayNames[:], value)
+		case stdMoonPhase:
+			_, value, ok = lookup(defaultMoonNames[:], value)
+		case stdMoonPercent:
+			var pct int
+			pct, value, ok = getnum(value, 1, 3)
+			if ok && pct > 100 {
+				rangeErr = "moon percent"
+			}
+		case stdFracSecond0:
+			// stdFracSecond0 requires the exact number of digits as
+			// specified in the layout.
+			ndigit := 1 + std>>stdArgShift
+			if len(value) < ndigit || (value[0] != '.' && value[0] != ',') {
+				break
+			}
+			ok = true
+			for i := 1; i < ndigit; i++ {
+				if !isDigit(value, i) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				usec = parseFrac(value[1:ndigit])
+				value = value[ndigit:]
+			}
+		case stdFracSecond9:
+			if len(value) < 2 || (value[0] != '.' && value[0] != ',') || !isDigit(value, 1) {
+				// Fractional second omitted.
+				ok = true
+				break
+			}
+			i := 1
+			for i < 1+std>>stdArgShift && isDigit(value, i) {
+				i++
+			}
+			usec = parseFrac(value[1:i])
+			value = value[i:]
+			ok = true
+		}
+		if rangeErr != "" {
+			return Time{}, &ParseError{alayout, avalue, stdstr, offset, rangeErr + " out of range"}
+		}
+		if !ok {
+			return Time{}, &ParseError{alayout, avalue, stdstr, offset, "cannot parse " + strconv.Quote(avalue[offset:])}
+		}
+	}
+
+	if yday >= 0 {
+		ymon, yd := (yday-1)/30+1, (yday-1)%30+1
+		if (mon >= 0 && ymon != mon) || (day >= 0 && yd != day) {
+			return Time{}, &ParseError{alayout, avalue, "033", ydayOffset, "day of year does not match month and day"}
+		}
+		mon, day = ymon, yd
+	}
+	if mon < 0 {
+		mon = 1
+	}
+	if day < 0 {
+		day = 1
+	}
+
+	return Date(year, mon, day, hour, min, sec, usec), nil
+}
+
+// stdDigits returns the minimum number of digits for the numerical std.
+func stdDigits(std int) int {
+	switch std {
+	case stdZeroMonth, stdZeroDay, stdZeroHour, stdZeroMinute, stdZeroSecond:
+		return 2
+	}
+	return 1
+}
+
+// skip removes the given prefix from value,
+// treating runs of space characters as equivalent.
+func skip(value, prefix string) (string, bool) {
+	for len(prefix) > 0 {
+		if prefix[0] == ' ' {
+			if len(value) > 0 && value[0] != ' ' {
+				return value, false
+			}
+			prefix = cutspace(prefix)
+			value = cutspace(value)
+			continue
+		}
+		if len(value) == 0 || value[0] != prefix[0] {
+			return value, false
+		}
+		prefix = prefix[1:]
+		value = value[1:]
+	}
+	return value, true
+}
+
+func cutspace(s string) string {
+	for len(s) > 0 && s[0] == ' ' {
+		s = s[1:]
+	}
+	return s
+}
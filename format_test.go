@@ -0,0 +1,144 @@
+package vanatime_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func TestFormatReference(t *testing.T) {
+	ref := vanatime.Date(1, 2, 3, 4, 5, 6, 0)
+	layouts := []string{
+		vanatime.Layout,
+		vanatime.DateTime,
+		vanatime.DateOnly,
+		vanatime.TimeOnly,
+	}
+
+	for i, layout := range layouts {
+		got := ref.Format(layout)
+		if got != layout {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, layout, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 654300)
+	patterns := []struct {
+		Layout string
+		Want   string
+	}{
+		{vanatime.Layout, "1313-04-13 21:20:27 Lightsday Waxing Crescent (33%)"},
+		{vanatime.DateTimeMicro, "1313-04-13 21:20:27.654300"},
+		{"0001/2/3 4:5:6", "1313/4/13 21:20:27"},
+		{"0001-02-_3", "1313-04-13"},
+		{"0001 033", "1313 103"},
+		{"04:05:06.000", "21:20:27.654"},
+		{"04:05:06.999999", "21:20:27.6543"},
+		{"04:05:06.00000000", "21:20:27.65430000"},
+		{"Firesday, Full Moon 95%", "Lightsday, Waxing Crescent 33%"},
+	}
+
+	for i, pattern := range patterns {
+		got := vt.Format(pattern.Layout)
+		if got != pattern.Want {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want, got)
+		}
+	}
+
+	if got, want := vanatime.Date(-1, 2, 3, 4, 5, 6, 0).Format(vanatime.DateTime), "-0001-02-03 04:05:06"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+	if got, want := vanatime.Date(1300, 2, 3, 0, 0, 0, 0).Format("_3 04:05:06.999"), " 3 00:00:00"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	patterns := []struct {
+		Layout string
+		Value  string
+		Want   vanatime.Time
+	}{
+		{vanatime.Layout, "1313-04-13 21:20:27 Lightsday Waxing Crescent (33%)", vanatime.Date(1313, 4, 13, 21, 20, 27, 0)},
+		{vanatime.DateTime, "1313-04-13 21:20:27", vanatime.Date(1313, 4, 13, 21, 20, 27, 0)},
+		{vanatime.DateTime, "1313-04-13 21:20:27.5", vanatime.Date(1313, 4, 13, 21, 20, 27, 500000)},
+		{vanatime.DateTimeMicro, "1313-04-13 21:20:27.654321", vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)},
+		{vanatime.DateOnly, "-0001-02-03", vanatime.Date(-1, 2, 3, 0, 0, 0, 0)},
+		{vanatime.TimeOnly, "21:20:27", vanatime.Date(1, 1, 1, 21, 20, 27, 0)},
+		{"0001/2/3 4:5:6", "1313/4/13 1:2:3", vanatime.Date(1313, 4, 13, 1, 2, 3, 0)},
+		{"0001-02-_3", "1313-04- 3", vanatime.Date(1313, 4, 3, 0, 0, 0, 0)},
+		{"0001 033", "1313 103", vanatime.Date(1313, 4, 13, 0, 0, 0, 0)},
+		{"000102030405", "131304132120", vanatime.Date(1313, 4, 13, 21, 20, 0, 0)},
+		{"04:05:06.999", "21:20:27", vanatime.Date(1, 1, 1, 21, 20, 27, 0)},
+		{"04:05:06.999", "21:20:27.12", vanatime.Date(1, 1, 1, 21, 20, 27, 120000)},
+		{"Firesday 0001-02-03", "LIGHTSDAY 1313-04-13", vanatime.Date(1313, 4, 13, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got, err := vanatime.Parse(pattern.Layout, pattern.Value)
+		if err != nil {
+			t.Errorf("[%d]: %s", i, err)
+			continue
+		}
+
+		if !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	patterns := []struct {
+		Layout  string
+		Value   string
+		Element string
+		Offset  int
+	}{
+		{vanatime.DateOnly, "1313-13-01", "02", 5},
+		{vanatime.DateOnly, "1313-12-31", "03", 8},
+		{vanatime.DateOnly, "1313-1-01", "02", 5},
+		{vanatime.DateOnly, "13-01-01", "0001", 0},
+		{vanatime.DateOnly, "1313/01/01", "", 4},
+		{vanatime.TimeOnly, "24:00:00", "04", 0},
+		{vanatime.TimeOnly, "00:00:00 extra", "", 8},
+		{vanatime.Layout, "1313-04-13 21:20:27 Sunday Waxing Crescent (33%)", "Firesday", 20},
+		{vanatime.Layout, "1313-04-13 21:20:27 Lightsday Half Moon (33%)", "Full Moon", 30},
+		{vanatime.Layout, "1313-04-13 21:20:27 Lightsday Waxing Crescent (101%)", "95", 47},
+		{"04:05:06.000", "21:20:27.1", ".000", 8},
+		{"0001-033 02", "1313-033 03", "033", 5},
+	}
+
+	for i, pattern := range patterns {
+		_, err := vanatime.Parse(pattern.Layout, pattern.Value)
+		if err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+			continue
+		}
+
+		perr, ok := err.(*vanatime.ParseError)
+		if !ok {
+			t.Errorf("[%d]: want *ParseError, but %T", i, err)
+			continue
+		}
+		if perr.Directive != pattern.Element || perr.Offset != pattern.Offset {
+			t.Errorf(`[%d]: want %q at %d, but %q at %d (%s)`, i, pattern.Element, pattern.Offset, perr.Directive, perr.Offset, err)
+		}
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	vt := vanatime.Date(886, 1, 1, 0, 0, 0, 0)
+	for i := 0; i < 100; i++ {
+		vt = vt.Add(7*vanatime.Day + 3*vanatime.Hour + 17*vanatime.Minute + 31*vanatime.Second + 12345*vanatime.Microsecond)
+		s := vt.Format(vanatime.DateTimeMicro)
+		got, err := vanatime.Parse(vanatime.DateTimeMicro, s)
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.Equal(vt) {
+			t.Fatalf(`[%d]: want "%v", but "%v"`, i, vt, got)
+		}
+	}
+}
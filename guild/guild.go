@@ -0,0 +1,150 @@
+// Package guild provides the opening hours of the crafting guilds and
+// other NPC shops in Vana'diel.
+//
+// Guilds open for fixed Vana'diel hours every day, except on their holiday.
+// The built-in guild schedules are provided as variables; schedules of other
+// shops can be described by creating a Schedule.
+package guild
+
+import (
+	"github.com/pasela/go-vanatime"
+)
+
+// A Schedule describes the opening hours of a shop.
+type Schedule struct {
+	Name string
+
+	// Open and Close are the opening and closing times within a Vana'diel
+	// day, as offsets from 00:00. If Close is not after Open, the shop
+	// closes on the following day. If both are equal, the shop is open all
+	// day.
+	Open  vanatime.Duration
+	Close vanatime.Duration
+
+	// Holidays are the days of the week on which the shop does not open.
+	Holidays []vanatime.Weekday
+}
+
+// Hours returns the offset from 00:00 of the given Vana'diel clock time,
+// for use in Schedule.
+func Hours(hour, min int) vanatime.Duration {
+	return vanatime.Duration(hour)*vanatime.Hour + vanatime.Duration(min)*vanatime.Minute
+}
+
+// The crafting guilds.
+var (
+	Alchemy      = Schedule{Name: "Alchemy", Open: Hours(8, 0), Close: Hours(23, 0), Holidays: []vanatime.Weekday{vanatime.Lightsday}}
+	Bonecraft    = Schedule{Name: "Bonecraft", Open: Hours(8, 0), Close: Hours(23, 0), Holidays: []vanatime.Weekday{vanatime.Windsday}}
+	Clothcraft   = Schedule{Name: "Clothcraft", Open: Hours(6, 0), Close: Hours(21, 0), Holidays: []vanatime.Weekday{vanatime.Firesday}}
+	Cooking      = Schedule{Name: "Cooking", Open: Hours(5, 0), Close: Hours(20, 0), Holidays: []vanatime.Weekday{vanatime.Darksday}}
+	Fishing      = Schedule{Name: "Fishing", Open: Hours(3, 0), Close: Hours(18, 0), Holidays: []vanatime.Weekday{vanatime.Lightsday}}
+	Goldsmithing = Schedule{Name: "Goldsmithing", Open: Hours(8, 0), Close: Hours(23, 0), Holidays: []vanatime.Weekday{vanatime.Lightningday}}
+	Leathercraft = Schedule{Name: "Leathercraft", Open: Hours(3, 0), Close: Hours(18, 0), Holidays: []vanatime.Weekday{vanatime.Iceday}}
+	Smithing     = Schedule{Name: "Smithing", Open: Hours(8, 0), Close: Hours(23, 0), Holidays: []vanatime.Weekday{vanatime.Watersday}}
+	Woodworking  = Schedule{Name: "Woodworking", Open: Hours(6, 0), Close: Hours(21, 0), Holidays: []vanatime.Weekday{vanatime.Darksday}}
+)
+
+// Guilds is the list of the crafting guilds.
+var Guilds = []Schedule{
+	Alchemy,
+	Bonecraft,
+	Clothcraft,
+	Cooking,
+	Fishing,
+	Goldsmithing,
+	Leathercraft,
+	Smithing,
+	Woodworking,
+}
+
+// Find returns the guild in Guilds with the given name.
+func Find(name string) (Schedule, bool) {
+	for _, g := range Guilds {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return Schedule{}, false
+}
+
+// IsOpen reports whether the shop is open at t.
+func (s Schedule) IsOpen(t vanatime.Time) bool {
+	start, end, ok := s.period(t.Truncate(vanatime.Day).Add(-vanatime.Day))
+	if ok && !t.Before(start) && t.Before(end) {
+		return true
+	}
+	start, end, ok = s.period(t.Truncate(vanatime.Day))
+	return ok && !t.Before(start) && t.Before(end)
+}
+
+// NextOpen returns the time at which the shop opens next after t.
+// If the shop is open at t, it is the opening after the current opening
+// hours. It returns the zero Time if the shop never opens.
+func (s Schedule) NextOpen(t vanatime.Time) vanatime.Time {
+	day := t.Truncate(vanatime.Day).Add(-vanatime.Day)
+	var prevEnd vanatime.Time
+	for i := 0; i < limit; i++ {
+		start, end, ok := s.period(day)
+		day = day.Add(vanatime.Day)
+		if !ok {
+			continue
+		}
+		// periods which continue the previous one are not openings
+		if start.After(t) && !start.Equal(prevEnd) {
+			return start
+		}
+		prevEnd = end
+	}
+	return vanatime.Time{}
+}
+
+// NextClose returns the time at which the shop closes next after t.
+// If the shop is open at t, it is the end of the current opening hours.
+// It returns the zero Time if the shop never closes, or never opens.
+func (s Schedule) NextClose(t vanatime.Time) vanatime.Time {
+	day := t.Truncate(vanatime.Day).Add(-vanatime.Day)
+	var closing vanatime.Time
+	for i := 0; i < limit; i++ {
+		start, end, ok := s.period(day)
+		day = day.Add(vanatime.Day)
+		if !ok {
+			if !closing.IsZero() {
+				return closing
+			}
+			continue
+		}
+		if !closing.IsZero() && !start.Equal(closing) {
+			return closing
+		}
+		if end.After(t) {
+			closing = end
+		}
+	}
+	return vanatime.Time{}
+}
+
+// limit is the number of days searched by NextOpen and NextClose,
+// long enough to cover a week and the days around it.
+const limit = 2*int(vanatime.Week/vanatime.Day) + 2
+
+// period returns the opening hours which start on the given day.
+func (s Schedule) period(day vanatime.Time) (start, end vanatime.Time, ok bool) {
+	if s.isHoliday(day.Weekday()) {
+		return
+	}
+	start = day.Add(s.Open)
+	end = day.Add(s.Close)
+	if !end.After(start) {
+		end = end.Add(vanatime.Day)
+	}
+	return start, end, true
+}
+
+func (s Schedule) isHoliday(w vanatime.Weekday) bool {
+	for _, h := range s.Holidays {
+		if h == w {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package guild_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/guild"
+)
+
+// 1313-04-09 is Watersday, the holiday of the Smithing guild.
+func TestIsOpen(t *testing.T) {
+	patterns := []struct {
+		T    vanatime.Time
+		Want bool
+	}{
+		{vanatime.Date(1313, 4, 8, 7, 59, 59, 999999), false},
+		{vanatime.Date(1313, 4, 8, 8, 0, 0, 0), true},
+		{vanatime.Date(1313, 4, 8, 22, 59, 59, 999999), true},
+		{vanatime.Date(1313, 4, 8, 23, 0, 0, 0), false},
+		{vanatime.Date(1313, 4, 9, 12, 0, 0, 0), false},
+		{vanatime.Date(1313, 4, 10, 12, 0, 0, 0), true},
+	}
+
+	if w := vanatime.Date(1313, 4, 9, 0, 0, 0, 0).Weekday(); w != vanatime.Watersday {
+		t.Fatalf("want Watersday, but %v", w)
+	}
+	for i, pattern := range patterns {
+		got := guild.Smithing.IsOpen(pattern.T)
+		if got != pattern.Want {
+			t.Errorf(`[%d]: "%v": want %v, but %v`, i, pattern.T, pattern.Want, got)
+		}
+	}
+}
+
+func TestNextOpenClose(t *testing.T) {
+	patterns := []struct {
+		T     vanatime.Time
+		Open  vanatime.Time
+		Close vanatime.Time
+	}{
+		{vanatime.Date(1313, 4, 8, 3, 0, 0, 0), vanatime.Date(1313, 4, 8, 8, 0, 0, 0), vanatime.Date(1313, 4, 8, 23, 0, 0, 0)},
+		// open: the next opening skips the holiday
+		{vanatime.Date(1313, 4, 8, 12, 0, 0, 0), vanatime.Date(1313, 4, 10, 8, 0, 0, 0), vanatime.Date(1313, 4, 8, 23, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 8, 8, 0, 0, 0), vanatime.Date(1313, 4, 10, 8, 0, 0, 0), vanatime.Date(1313, 4, 8, 23, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 9, 12, 0, 0, 0), vanatime.Date(1313, 4, 10, 8, 0, 0, 0), vanatime.Date(1313, 4, 10, 23, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 10, 23, 0, 0, 0), vanatime.Date(1313, 4, 11, 8, 0, 0, 0), vanatime.Date(1313, 4, 11, 23, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		if got := guild.Smithing.NextOpen(pattern.T); !got.Equal(pattern.Open) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Open, got)
+		}
+		if got := guild.Smithing.NextClose(pattern.T); !got.Equal(pattern.Close) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Close, got)
+		}
+	}
+}
+
+func TestOvernightSchedule(t *testing.T) {
+	tavern := guild.Schedule{
+		Name:     "Tavern",
+		Open:     guild.Hours(20, 0),
+		Close:    guild.Hours(4, 0),
+		Holidays: []vanatime.Weekday{vanatime.Watersday},
+	}
+
+	patterns := []struct {
+		T     vanatime.Time
+		Want  bool
+		Open  vanatime.Time
+		Close vanatime.Time
+	}{
+		{vanatime.Date(1313, 4, 8, 21, 0, 0, 0), true, vanatime.Date(1313, 4, 10, 20, 0, 0, 0), vanatime.Date(1313, 4, 9, 4, 0, 0, 0)},
+		// opened on Earthsday, so open until 04:00 of the holiday
+		{vanatime.Date(1313, 4, 9, 3, 0, 0, 0), true, vanatime.Date(1313, 4, 10, 20, 0, 0, 0), vanatime.Date(1313, 4, 9, 4, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 9, 21, 0, 0, 0), false, vanatime.Date(1313, 4, 10, 20, 0, 0, 0), vanatime.Date(1313, 4, 11, 4, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 10, 12, 0, 0, 0), false, vanatime.Date(1313, 4, 10, 20, 0, 0, 0), vanatime.Date(1313, 4, 11, 4, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		if got := tavern.IsOpen(pattern.T); got != pattern.Want {
+			t.Errorf(`[%d]: want %v, but %v`, i, pattern.Want, got)
+		}
+		if got := tavern.NextOpen(pattern.T); !got.Equal(pattern.Open) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Open, got)
+		}
+		if got := tavern.NextClose(pattern.T); !got.Equal(pattern.Close) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Close, got)
+		}
+	}
+}
+
+func TestAllDaySchedule(t *testing.T) {
+	vendor := guild.Schedule{
+		Name:     "Vendor",
+		Holidays: []vanatime.Weekday{vanatime.Darksday},
+	}
+	// 1313-04-13 is Lightsday, followed by Darksday
+	vt := vanatime.Date(1313, 4, 13, 12, 0, 0, 0)
+
+	if !vendor.IsOpen(vt) {
+		t.Errorf("want open, but closed")
+	}
+	if got, want := vendor.NextClose(vt), vanatime.Date(1313, 4, 14, 0, 0, 0, 0); !got.Equal(want) {
+		t.Errorf(`want "%v", but "%v"`, want, got)
+	}
+	if got, want := vendor.NextOpen(vt), vanatime.Date(1313, 4, 15, 0, 0, 0, 0); !got.Equal(want) {
+		t.Errorf(`want "%v", but "%v"`, want, got)
+	}
+
+	always := guild.Schedule{Name: "Always"}
+	if got := always.NextClose(vt); !got.IsZero() {
+		t.Errorf(`want zero time, but "%v"`, got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	g, ok := guild.Find("Fishing")
+	if !ok || g.Name != "Fishing" {
+		t.Errorf("Fishing guild not found")
+	}
+	if _, ok := guild.Find("Cartography"); ok {
+		t.Errorf("unexpected guild found")
+	}
+	if len(guild.Guilds) != 9 {
+		t.Errorf("want 9 guilds, but %d", len(guild.Guilds))
+	}
+}
@@ -0,0 +1,305 @@
+// Package ical writes Vana'diel events as iCalendar (RFC 5545) data, so
+// that they can be imported into calendar applications.
+//
+// The times are written in Earth UTC, computed with vanatime.Time.Earth and
+// truncated to the second, the precision of iCalendar. The Vana'diel times
+// of an event are included in its DESCRIPTION.
+//
+// A recurring event is written as a list of its occurrences (RDATE), which
+// every application understands. Recurrences of a fixed period can instead
+// be written as a rule with FREQ=SECONDLY by setting
+// Calendar.SecondlyRules, but many applications, such as Google Calendar
+// and Outlook, ignore such rules.
+//
+//     cal := &ical.Calendar{Name: "Vana'diel"}
+//     cal.Add(ical.NewEvent("Conquest tally", vanatime.Interval{Start: tally, End: tally}))
+//     cal.WriteTo(os.Stdout)
+package ical
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// DefaultProdID is the product identifier written when Calendar.ProdID is
+// empty.
+const DefaultProdID = "-//pasela//go-vanatime//EN"
+
+// DefaultMaxOccurrences is the number of occurrences written for a
+// recurrence when Calendar.MaxOccurrences is zero.
+const DefaultMaxOccurrences = 100
+
+// An Event is an event in Vana'diel time.
+type Event struct {
+	// UID identifies the event. If empty, a UID is derived from the
+	// summary, the start and the recurrence, so that the same event gets
+	// the same UID in every export.
+	UID string
+
+	Summary     string
+	Description string
+	Location    string
+
+	// Start and End are the bounds of the event. If End is not after
+	// Start, the event is an instant and has no end.
+	Start vanatime.Time
+	End   vanatime.Time
+
+	// Recurrence optionally repeats the event. Its Start is replaced by
+	// the Start of the event, and every occurrence has the same length as
+	// the event.
+	Recurrence *vanatime.Recurrence
+}
+
+// NewEvent returns an event for the interval.
+func NewEvent(summary string, i vanatime.Interval) Event {
+	return Event{
+		Summary: summary,
+		Start:   i.Start,
+		End:     i.End,
+	}
+}
+
+// Interval returns the interval of the event.
+func (e Event) Interval() vanatime.Interval {
+	return vanatime.Interval{Start: e.Start, End: e.End}
+}
+
+// uid returns the UID of the event.
+func (e Event) uid() string {
+	if e.UID != "" {
+		return e.UID
+	}
+	h := sha1.New()
+	io.WriteString(h, e.Summary)
+	io.WriteString(h, "\x00"+strconv.FormatInt(e.Start.Int64(), 10))
+	io.WriteString(h, "\x00"+strconv.FormatInt(e.End.Int64(), 10))
+	if e.Recurrence != nil {
+		io.WriteString(h, "\x00"+e.Recurrence.String())
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "@go-vanatime"
+}
+
+// A Calendar is a collection of events.
+type Calendar struct {
+	ProdID string // product identifier; DefaultProdID if empty
+	Name   string // name of the calendar shown by applications, optional
+
+	Events []Event
+
+	// Stamp is written as the DTSTAMP of the events. If zero, the current
+	// time is used.
+	Stamp time.Time
+
+	// MaxOccurrences limits the occurrences written for a recurrence
+	// which is written as a list of dates. If zero, DefaultMaxOccurrences
+	// is used.
+	MaxOccurrences int
+
+	// SecondlyRules writes the recurrences which repeat at a fixed period
+	// as an RRULE with FREQ=SECONDLY rather than as a list of dates. The
+	// rule covers every occurrence, but is not supported by many
+	// applications.
+	SecondlyRules bool
+}
+
+// Add adds the events to the calendar.
+func (c *Calendar) Add(events ...Event) {
+	c.Events = append(c.Events, events...)
+}
+
+// WriteTo writes the calendar to w as iCalendar data.
+// It implements the io.WriterTo interface.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &writer{w: bufio.NewWriter(w)}
+
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = DefaultProdID
+	}
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", escape(prodID))
+	cw.line("CALSCALE", "GREGORIAN")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME", escape(c.Name))
+	}
+	for _, e := range c.Events {
+		c.writeEvent(cw, e, stamp)
+	}
+	cw.line("END", "VCALENDAR")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (c *Calendar) writeEvent(cw *writer, e Event, stamp time.Time) {
+	length := e.Interval().Duration()
+
+	var rrule string
+	var rdates []vanatime.Time
+	if e.Recurrence != nil {
+		r := *e.Recurrence
+		r.Start = e.Start
+		if c.SecondlyRules {
+			rrule = earthRule(r)
+		}
+		if rrule == "" {
+			max := c.MaxOccurrences
+			if max <= 0 {
+				max = DefaultMaxOccurrences
+			}
+			it := r.Iter()
+			for len(rdates) < max && it.Next() {
+				rdates = append(rdates, it.Time())
+			}
+			if len(rdates) == 0 {
+				return
+			}
+			e.Start, rdates = rdates[0], rdates[1:]
+		}
+	}
+
+	desc := "Vana'diel time: " + e.Start.String()
+	if length > 0 {
+		desc += " - " + e.Start.Add(length).String()
+	}
+	if e.Recurrence != nil {
+		r := *e.Recurrence
+		r.Start = e.Start
+		desc += "\nRepeats: " + r.String()
+	}
+	if e.Description != "" {
+		desc += "\n\n" + e.Description
+	}
+
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", escape(e.uid()))
+	cw.line("DTSTAMP", formatTime(stamp))
+	cw.line("DTSTART", earthTime(e.Start))
+	if length > 0 {
+		cw.line("DTEND", earthTime(e.Start.Add(length)))
+	}
+	if rrule != "" {
+		cw.line("RRULE", rrule)
+	}
+	if len(rdates) > 0 {
+		dates := make([]string, len(rdates))
+		for i, t := range rdates {
+			dates[i] = earthTime(t)
+		}
+		cw.line("RDATE", strings.Join(dates, ","))
+	}
+	cw.line("SUMMARY", escape(e.Summary))
+	cw.line("DESCRIPTION", escape(desc))
+	if e.Location != "" {
+		cw.line("LOCATION", escape(e.Location))
+	}
+	cw.line("END", "VEVENT")
+}
+
+// earthRule returns the iCalendar rule equivalent to r, or "" if there is
+// none. The Vana'diel hours, days, weeks, months and years have a fixed
+// length of whole Earth seconds (144 seconds for an hour), so rules which
+// only repeat at a fixed period can be written with FREQ=SECONDLY.
+func earthRule(r vanatime.Recurrence) string {
+	if r.Validate() != nil || len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+		return ""
+	}
+
+	var period vanatime.Duration
+	switch r.Freq {
+	case vanatime.Hourly:
+		period = vanatime.Hour
+	case vanatime.Daily:
+		period = vanatime.Day
+	case vanatime.Weekly:
+		period = vanatime.Week
+	case vanatime.Monthly:
+		period = vanatime.Month
+	case vanatime.Yearly:
+		period = vanatime.Year
+	}
+	if r.Interval > 1 {
+		period *= vanatime.Duration(r.Interval)
+	}
+	seconds := int64(period) / int64(vanatime.Second) / int64(vanatime.TimeScale)
+
+	rule := "FREQ=SECONDLY;INTERVAL=" + strconv.FormatInt(seconds, 10)
+	if r.Count > 0 {
+		rule += ";COUNT=" + strconv.Itoa(r.Count)
+	}
+	if !r.Until.IsZero() {
+		rule += ";UNTIL=" + earthTime(r.Until)
+	}
+	return rule
+}
+
+// earthTime returns the Earth UTC time of t in the iCalendar format.
+func earthTime(t vanatime.Time) string {
+	return formatTime(t.Earth())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a text value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// A writer writes content lines, folded at 75 octets and terminated by
+// CRLF. It stops at the first error.
+type writer struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+const maxLineOctets = 75
+
+func (cw *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLineOctets
+	for len(s) > limit {
+		// do not split a UTF-8 sequence
+		i := limit
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		cw.write(s[:i] + "\r\n ")
+		s = s[i:]
+		limit = maxLineOctets - 1 // after the leading space
+	}
+	cw.write(s + "\r\n")
+}
+
+func (cw *writer) write(s string) {
+	if cw.err != nil {
+		return
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
@@ -0,0 +1,192 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/ical"
+)
+
+func earth(t vanatime.Time) string {
+	return t.Earth().UTC().Format("20060102T150405Z")
+}
+
+// unfold returns the content lines of iCalendar data, checking the line
+// length and the line terminators.
+func unfold(t *testing.T, data string) []string {
+	if !strings.HasSuffix(data, "\r\n") {
+		t.Fatalf("data does not end with CRLF")
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %q", l)
+		}
+		if !utf8.ValidString(l) {
+			t.Errorf("line splits a UTF-8 sequence: %q", l)
+		}
+		if strings.HasPrefix(l, " ") {
+			lines[len(lines)-1] += l[1:]
+		} else {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func find(lines []string, name string) []string {
+	var values []string
+	for _, l := range lines {
+		if strings.HasPrefix(l, name+":") {
+			values = append(values, strings.TrimPrefix(l, name+":"))
+		}
+	}
+	return values
+}
+
+func TestCalendar(t *testing.T) {
+	// 1313-04-15 is Firesday
+	tally := vanatime.Date(1313, 4, 15, 0, 0, 0, 0)
+	open := vanatime.Date(1313, 4, 13, 8, 0, 0, 0)
+	guild := ical.NewEvent("Smithing guild; open", vanatime.Interval{Start: open, End: open.Add(15 * vanatime.Hour)})
+	guild.Location = "Bastok, Metalworks"
+	guild.Description = "Closed on Watersday"
+	guild.Recurrence = &vanatime.Recurrence{Freq: vanatime.Daily, Count: 5}
+
+	monthly := ical.Event{
+		UID:        "first-firesday@example.com",
+		Summary:    "First Firesday",
+		Start:      open,
+		Recurrence: &vanatime.Recurrence{Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{N: 1, Weekday: vanatime.Firesday}}},
+	}
+
+	cal := &ical.Calendar{
+		Name:           "Vana'diel",
+		Stamp:          time.Date(2018, 11, 5, 12, 0, 0, 0, time.UTC),
+		MaxOccurrences: 3,
+	}
+	cal.Add(ical.NewEvent("Conquest tally", vanatime.Interval{Start: tally, End: tally}), guild, monthly)
+
+	var buf bytes.Buffer
+	n, err := cal.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("wrote %d bytes, but reported %d", buf.Len(), n)
+	}
+	lines := unfold(t, buf.String())
+
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Errorf("unexpected calendar %q ... %q", lines[0], lines[len(lines)-1])
+	}
+	if got := find(lines, "BEGIN"); len(got) != 4 {
+		t.Errorf("want 3 events, but %v", got)
+	}
+	if got := find(lines, "DTSTAMP"); len(got) != 3 || got[0] != "20181105T120000Z" {
+		t.Errorf("unexpected DTSTAMP %v", got)
+	}
+
+	start := find(lines, "DTSTART")
+	end := find(lines, "DTEND")
+	want := []string{earth(tally), earth(open), earth(vanatime.Date(1313, 5, 1, 8, 0, 0, 0))}
+	if len(start) != 3 || start[0] != want[0] || start[1] != want[1] || start[2] != want[2] {
+		t.Errorf("want DTSTART %v, but %v", want, start)
+	}
+	if len(end) != 1 || end[0] != earth(open.Add(15*vanatime.Hour)) {
+		t.Errorf("unexpected DTEND %v", end)
+	}
+
+	// the recurrences are written as dates, up to MaxOccurrences
+	if got := find(lines, "RRULE"); len(got) != 0 {
+		t.Errorf("unexpected RRULE %v", got)
+	}
+	wantRDate := []string{
+		earth(vanatime.Date(1313, 4, 14, 8, 0, 0, 0)) + "," + earth(vanatime.Date(1313, 4, 15, 8, 0, 0, 0)),
+		earth(vanatime.Date(1313, 6, 3, 8, 0, 0, 0)) + "," + earth(vanatime.Date(1313, 7, 5, 8, 0, 0, 0)),
+	}
+	if got := find(lines, "RDATE"); len(got) != 2 || got[0] != wantRDate[0] || got[1] != wantRDate[1] {
+		t.Errorf("want RDATE %v, but %v", wantRDate, got)
+	}
+
+	if got := find(lines, "SUMMARY"); len(got) != 3 || got[1] != `Smithing guild\; open` {
+		t.Errorf("unexpected SUMMARY %v", got)
+	}
+	if got := find(lines, "LOCATION"); len(got) != 1 || got[0] != `Bastok\, Metalworks` {
+		t.Errorf("unexpected LOCATION %v", got)
+	}
+	desc := find(lines, "DESCRIPTION")
+	if len(desc) != 3 {
+		t.Fatalf("unexpected DESCRIPTION %v", desc)
+	}
+	if !strings.HasPrefix(desc[0], "Vana'diel time: 1313-04-15 00:00:00 Firesday") {
+		t.Errorf("unexpected DESCRIPTION %q", desc[0])
+	}
+	if !strings.Contains(desc[1], `1313-04-13 08:00:00 Lightsday`) || !strings.HasSuffix(desc[1], `\n\nClosed on Watersday`) {
+		t.Errorf("unexpected DESCRIPTION %q", desc[1])
+	}
+
+	uids := find(lines, "UID")
+	if len(uids) != 3 || uids[0] == uids[1] || uids[2] != "first-firesday@example.com" {
+		t.Errorf("unexpected UID %v", uids)
+	}
+
+	// UIDs are stable
+	buf.Reset()
+	cal.Stamp = time.Time{}
+	if _, err := cal.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	again := find(unfold(t, buf.String()), "UID")
+	for i := range uids {
+		if again[i] != uids[i] {
+			t.Errorf("[%d]: UID changed from %q to %q", i, uids[i], again[i])
+		}
+	}
+}
+
+func TestSecondlyRules(t *testing.T) {
+	open := vanatime.Date(1313, 4, 13, 8, 0, 0, 0)
+	daily := ical.NewEvent("Daily", vanatime.Interval{Start: open, End: open.Add(vanatime.Hour)})
+	daily.Recurrence = &vanatime.Recurrence{Freq: vanatime.Daily, Count: 5}
+	monthly := ical.NewEvent("First Firesday", vanatime.Interval{Start: open})
+	monthly.Recurrence = &vanatime.Recurrence{Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{N: 1, Weekday: vanatime.Firesday}}}
+
+	cal := &ical.Calendar{SecondlyRules: true, MaxOccurrences: 3}
+	cal.Add(daily, monthly)
+
+	var buf bytes.Buffer
+	if _, err := cal.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := unfold(t, buf.String())
+
+	// a Vana'diel day is 3456 seconds on the Earth
+	if got := find(lines, "RRULE"); len(got) != 1 || got[0] != "FREQ=SECONDLY;INTERVAL=3456;COUNT=5" {
+		t.Errorf("unexpected RRULE %v", got)
+	}
+	// a recurrence without a fixed period is still written as dates
+	wantRDate := earth(vanatime.Date(1313, 6, 3, 8, 0, 0, 0)) + "," + earth(vanatime.Date(1313, 7, 5, 8, 0, 0, 0))
+	if got := find(lines, "RDATE"); len(got) != 1 || got[0] != wantRDate {
+		t.Errorf("want RDATE %v, but %v", wantRDate, got)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	summary := strings.Repeat("火曜日 ", 40)
+	cal := &ical.Calendar{}
+	cal.Add(ical.NewEvent(summary, vanatime.Interval{}))
+
+	var buf bytes.Buffer
+	if _, err := cal.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := find(unfold(t, buf.String()), "SUMMARY")
+	if len(got) != 1 || got[0] != summary {
+		t.Errorf("want %q, but %q", summary, got)
+	}
+}
@@ -0,0 +1,204 @@
+package vanatime
+
+import (
+	"sort"
+	"time"
+)
+
+// An Interval represents the half-open range of Vana'diel time
+// [Start, End). An interval whose End is not after its Start is empty.
+type Interval struct {
+	Start Time
+	End   Time
+}
+
+// IsEmpty reports whether the interval contains no time.
+func (i Interval) IsEmpty() bool {
+	return !i.Start.Before(i.End)
+}
+
+// Duration returns the length of the interval, or 0 if it is empty.
+func (i Interval) Duration() Duration {
+	if i.IsEmpty() {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Contains reports whether t is in the interval.
+func (i Interval) Contains(t Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// Overlaps reports whether the intervals have any time in common.
+func (i Interval) Overlaps(j Interval) bool {
+	return !i.Intersect(j).IsEmpty()
+}
+
+// Intersect returns the time common to both intervals. It returns the zero
+// Interval if the intervals do not overlap.
+func (i Interval) Intersect(j Interval) Interval {
+	r := i
+	if j.Start.After(r.Start) {
+		r.Start = j.Start
+	}
+	if j.End.Before(r.End) {
+		r.End = j.End
+	}
+	if r.IsEmpty() {
+		return Interval{}
+	}
+	return r
+}
+
+// Union returns the interval covering both intervals. ok is false if the
+// intervals neither overlap nor adjoin, as their union is not an interval;
+// use an IntervalSet to hold such unions. An empty interval is ignored.
+func (i Interval) Union(j Interval) (u Interval, ok bool) {
+	switch {
+	case j.IsEmpty():
+		return i, true
+	case i.IsEmpty():
+		return j, true
+	case i.Start.After(j.End) || j.Start.After(i.End):
+		return Interval{}, false
+	}
+	u = i
+	if j.Start.Before(u.Start) {
+		u.Start = j.Start
+	}
+	if j.End.After(u.End) {
+		u.End = j.End
+	}
+	return u, true
+}
+
+// Split splits the interval at every multiple of d since the zero time,
+// such as every 00:00 for Day. It returns nil if the interval is empty, and
+// the interval itself if d <= 0.
+func (i Interval) Split(d Duration) []Interval {
+	if i.IsEmpty() {
+		return nil
+	}
+	if d <= 0 {
+		return []Interval{i}
+	}
+
+	var parts []Interval
+	start := i.Start
+	for start.Before(i.End) {
+		end := Time{(floorDiv(start.time, int64(d)) + 1) * int64(d)}
+		if end.After(i.End) {
+			end = i.End
+		}
+		parts = append(parts, Interval{Start: start, End: end})
+		start = end
+	}
+	return parts
+}
+
+// SplitDays splits the interval at every 00:00 of the Vana'diel days.
+func (i Interval) SplitDays() []Interval {
+	return i.Split(Day)
+}
+
+// SplitWeeks splits the interval at every start of the Vana'diel weeks,
+// that is, every 00:00 of Firesday.
+func (i Interval) SplitWeeks() []Interval {
+	return i.Split(Week)
+}
+
+// Earth returns the Earth times of the bounds of the interval.
+func (i Interval) Earth() (start, end time.Time) {
+	return i.Start.Earth(), i.End.Earth()
+}
+
+// String returns the interval formatted as "[start, end)".
+func (i Interval) String() string {
+	return "[" + i.Start.String() + ", " + i.End.String() + ")"
+}
+
+// An IntervalSet is a set of Vana'diel time, held as a normalized list of
+// intervals: the intervals are sorted, non-empty, and neither overlap nor
+// adjoin each other. The zero value is an empty set.
+type IntervalSet struct {
+	intervals []Interval
+}
+
+// NewIntervalSet returns the set of the time in the given intervals.
+func NewIntervalSet(intervals ...Interval) IntervalSet {
+	var s IntervalSet
+	s.Add(intervals...)
+	return s
+}
+
+// Add adds the time in the given intervals to the set.
+func (s *IntervalSet) Add(intervals ...Interval) {
+	all := append(append([]Interval(nil), s.intervals...), intervals...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Start.Before(all[j].Start)
+	})
+
+	s.intervals = nil
+	for _, i := range all {
+		if i.IsEmpty() {
+			continue
+		}
+		if n := len(s.intervals); n > 0 {
+			if u, ok := s.intervals[n-1].Union(i); ok {
+				s.intervals[n-1] = u
+				continue
+			}
+		}
+		s.intervals = append(s.intervals, i)
+	}
+}
+
+// Intervals returns the normalized intervals of the set.
+func (s IntervalSet) Intervals() []Interval {
+	return append([]Interval(nil), s.intervals...)
+}
+
+// IsEmpty reports whether the set contains no time.
+func (s IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Contains reports whether t is in the set.
+func (s IntervalSet) Contains(t Time) bool {
+	i := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].End.After(t)
+	})
+	return i < len(s.intervals) && s.intervals[i].Contains(t)
+}
+
+// Duration returns the total length of the time in the set.
+func (s IntervalSet) Duration() Duration {
+	var d Duration
+	for _, i := range s.intervals {
+		d += i.Duration()
+	}
+	return d
+}
+
+// Union returns the set of the time in s or t.
+func (s IntervalSet) Union(t IntervalSet) IntervalSet {
+	return NewIntervalSet(append(s.Intervals(), t.intervals...)...)
+}
+
+// Intersect returns the set of the time in both s and t.
+func (s IntervalSet) Intersect(t IntervalSet) IntervalSet {
+	var r IntervalSet
+	a, b := s.intervals, t.intervals
+	for len(a) > 0 && len(b) > 0 {
+		if i := a[0].Intersect(b[0]); !i.IsEmpty() {
+			r.intervals = append(r.intervals, i)
+		}
+		if a[0].End.Before(b[0].End) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+	return r
+}
@@ -0,0 +1,159 @@
+package vanatime_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func hm(day, hour, min int) vanatime.Time {
+	return vanatime.Date(1313, 4, day, hour, min, 0, 0)
+}
+
+func TestInterval(t *testing.T) {
+	i := vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 18, 0)}
+
+	if got := i.Duration(); got != 12*vanatime.Hour {
+		t.Errorf("want %v, but %v", 12*vanatime.Hour, got)
+	}
+	if !i.Contains(hm(13, 6, 0)) || !i.Contains(hm(13, 17, 59)) || i.Contains(hm(13, 18, 0)) || i.Contains(hm(13, 5, 59)) {
+		t.Errorf("unexpected Contains of %v", i)
+	}
+
+	empty := vanatime.Interval{Start: hm(13, 18, 0), End: hm(13, 6, 0)}
+	if !empty.IsEmpty() || empty.Duration() != 0 || empty.Contains(hm(13, 12, 0)) {
+		t.Errorf("%v is not empty", empty)
+	}
+
+	start, end := i.Earth()
+	if !start.Equal(hm(13, 6, 0).Earth()) || !end.Equal(hm(13, 18, 0).Earth()) {
+		t.Errorf("want %v - %v, but %v - %v", hm(13, 6, 0).Earth(), hm(13, 18, 0).Earth(), start, end)
+	}
+}
+
+func TestIntervalIntersectUnion(t *testing.T) {
+	i := vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 18, 0)}
+	patterns := []struct {
+		J         vanatime.Interval
+		Overlaps  bool
+		Intersect vanatime.Interval
+		Union     vanatime.Interval
+		OK        bool
+	}{
+		{
+			vanatime.Interval{Start: hm(13, 12, 0), End: hm(13, 20, 0)}, true,
+			vanatime.Interval{Start: hm(13, 12, 0), End: hm(13, 18, 0)},
+			vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 20, 0)}, true,
+		},
+		{
+			vanatime.Interval{Start: hm(13, 8, 0), End: hm(13, 9, 0)}, true,
+			vanatime.Interval{Start: hm(13, 8, 0), End: hm(13, 9, 0)},
+			i, true,
+		},
+		// adjoining
+		{
+			vanatime.Interval{Start: hm(13, 18, 0), End: hm(13, 20, 0)}, false,
+			vanatime.Interval{},
+			vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 20, 0)}, true,
+		},
+		// disjoint
+		{
+			vanatime.Interval{Start: hm(13, 0, 0), End: hm(13, 5, 0)}, false,
+			vanatime.Interval{},
+			vanatime.Interval{}, false,
+		},
+	}
+
+	for n, pattern := range patterns {
+		if got := i.Overlaps(pattern.J); got != pattern.Overlaps {
+			t.Errorf("[%d]: want %v, but %v", n, pattern.Overlaps, got)
+		}
+		if got := i.Intersect(pattern.J); got != pattern.Intersect {
+			t.Errorf("[%d]: want %v, but %v", n, pattern.Intersect, got)
+		}
+		if got := pattern.J.Intersect(i); got != pattern.Intersect {
+			t.Errorf("[%d]: want %v, but %v", n, pattern.Intersect, got)
+		}
+		if got, ok := i.Union(pattern.J); got != pattern.Union || ok != pattern.OK {
+			t.Errorf("[%d]: want %v (%v), but %v (%v)", n, pattern.Union, pattern.OK, got, ok)
+		}
+	}
+}
+
+func TestIntervalSplit(t *testing.T) {
+	// 1313-04-13 is Lightsday, and 1313-04-15 is Firesday
+	i := vanatime.Interval{Start: hm(13, 20, 0), End: hm(16, 6, 0)}
+
+	days := i.SplitDays()
+	wantDays := []vanatime.Interval{
+		{Start: hm(13, 20, 0), End: hm(14, 0, 0)},
+		{Start: hm(14, 0, 0), End: hm(15, 0, 0)},
+		{Start: hm(15, 0, 0), End: hm(16, 0, 0)},
+		{Start: hm(16, 0, 0), End: hm(16, 6, 0)},
+	}
+	if len(days) != len(wantDays) {
+		t.Fatalf("want %v, but %v", wantDays, days)
+	}
+	for n := range wantDays {
+		if days[n] != wantDays[n] {
+			t.Errorf("[%d]: want %v, but %v", n, wantDays[n], days[n])
+		}
+	}
+
+	weeks := i.SplitWeeks()
+	if len(weeks) != 2 || weeks[0].End != hm(15, 0, 0) || weeks[1].Start != hm(15, 0, 0) {
+		t.Errorf("unexpected weeks %v", weeks)
+	}
+
+	if got := (vanatime.Interval{}).SplitDays(); got != nil {
+		t.Errorf("want nil, but %v", got)
+	}
+}
+
+func TestIntervalSet(t *testing.T) {
+	s := vanatime.NewIntervalSet(
+		vanatime.Interval{Start: hm(13, 12, 0), End: hm(13, 14, 0)},
+		vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 8, 0)},
+		vanatime.Interval{Start: hm(13, 7, 0), End: hm(13, 10, 0)},
+		vanatime.Interval{Start: hm(13, 14, 0), End: hm(13, 15, 0)},
+		vanatime.Interval{Start: hm(13, 20, 0), End: hm(13, 20, 0)},
+	)
+	want := []vanatime.Interval{
+		{Start: hm(13, 6, 0), End: hm(13, 10, 0)},
+		{Start: hm(13, 12, 0), End: hm(13, 15, 0)},
+	}
+	got := s.Intervals()
+	if len(got) != len(want) {
+		t.Fatalf("want %v, but %v", want, got)
+	}
+	for n := range want {
+		if got[n] != want[n] {
+			t.Errorf("[%d]: want %v, but %v", n, want[n], got[n])
+		}
+	}
+	if d := s.Duration(); d != 7*vanatime.Hour {
+		t.Errorf("want %v, but %v", 7*vanatime.Hour, d)
+	}
+	if !s.Contains(hm(13, 9, 0)) || s.Contains(hm(13, 11, 0)) || !s.Contains(hm(13, 14, 30)) || s.Contains(hm(13, 15, 0)) {
+		t.Errorf("unexpected Contains of %v", got)
+	}
+
+	// a play session overlapping the opening hours
+	session := vanatime.NewIntervalSet(vanatime.Interval{Start: hm(13, 9, 0), End: hm(13, 13, 0)})
+	inter := s.Intersect(session).Intervals()
+	if len(inter) != 2 ||
+		inter[0] != (vanatime.Interval{Start: hm(13, 9, 0), End: hm(13, 10, 0)}) ||
+		inter[1] != (vanatime.Interval{Start: hm(13, 12, 0), End: hm(13, 13, 0)}) {
+		t.Errorf("unexpected intersection %v", inter)
+	}
+
+	union := s.Union(session).Intervals()
+	if len(union) != 1 || union[0] != (vanatime.Interval{Start: hm(13, 6, 0), End: hm(13, 15, 0)}) {
+		t.Errorf("unexpected union %v", union)
+	}
+
+	var empty vanatime.IntervalSet
+	if !empty.IsEmpty() || !s.Intersect(empty).IsEmpty() || empty.Contains(hm(13, 9, 0)) {
+		t.Errorf("empty set is not empty")
+	}
+}
@@ -0,0 +1,158 @@
+package vanatime
+
+import (
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// A Catalog holds the names used to display Vana'diel time in a locale.
+type Catalog struct {
+	Weekdays     [8]string  // full weekday names, Firesday first (%A)
+	WeekdayAbbrs [8]string  // abbreviated weekday names, Firesday first (%a)
+	MoonPhases   [12]string // moon phase names, New Moon first
+	Elements     [8]string  // element names, Fire first
+}
+
+var english = Catalog{
+	Weekdays:     defaultDayNames,
+	WeekdayAbbrs: defaultDayAbbrs,
+	MoonPhases:   defaultMoonNames,
+	Elements:     defaultElementNames,
+}
+
+var japanese = Catalog{
+	Weekdays:     [8]string{"火曜日", "土曜日", "水曜日", "風曜日", "氷曜日", "雷曜日", "光曜日", "闇曜日"},
+	WeekdayAbbrs: [8]string{"火", "土", "水", "風", "氷", "雷", "光", "闇"},
+	MoonPhases: [12]string{
+		"新月",
+		"三日月",
+		"七日月",
+		"上弦の月",
+		"十日夜",
+		"十三夜",
+		"満月",
+		"十六夜",
+		"居待月",
+		"下弦の月",
+		"二十日余月",
+		"二十六夜",
+	},
+	Elements: [8]string{"火", "土", "水", "風", "氷", "雷", "光", "闇"},
+}
+
+var french = Catalog{
+	Weekdays: [8]string{
+		"Jour de feu",
+		"Jour de terre",
+		"Jour d'eau",
+		"Jour de vent",
+		"Jour de glace",
+		"Jour de foudre",
+		"Jour de lumière",
+		"Jour des ténèbres",
+	},
+	WeekdayAbbrs: [8]string{"Feu", "Ter", "Eau", "Ven", "Gla", "Fou", "Lum", "Tén"},
+	MoonPhases: [12]string{
+		"Nouvelle lune",
+		"Premier croissant",
+		"Premier croissant",
+		"Premier quartier",
+		"Gibbeuse croissante",
+		"Gibbeuse croissante",
+		"Pleine lune",
+		"Gibbeuse décroissante",
+		"Gibbeuse décroissante",
+		"Dernier quartier",
+		"Dernier croissant",
+		"Dernier croissant",
+	},
+	Elements: [8]string{"Feu", "Terre", "Eau", "Vent", "Glace", "Foudre", "Lumière", "Ténèbres"},
+}
+
+var german = Catalog{
+	Weekdays: [8]string{
+		"Feuertag",
+		"Erdtag",
+		"Wassertag",
+		"Windtag",
+		"Eistag",
+		"Blitztag",
+		"Lichttag",
+		"Dunkeltag",
+	},
+	WeekdayAbbrs: [8]string{"Feu", "Erd", "Was", "Win", "Eis", "Bli", "Lic", "Dun"},
+	MoonPhases: [12]string{
+		"Neumond",
+		"Zunehmende Sichel",
+		"Zunehmende Sichel",
+		"Erstes Viertel",
+		"Zunehmender Mond",
+		"Zunehmender Mond",
+		"Vollmond",
+		"Abnehmender Mond",
+		"Abnehmender Mond",
+		"Letztes Viertel",
+		"Abnehmende Sichel",
+		"Abnehmende Sichel",
+	},
+	Elements: [8]string{"Feuer", "Erde", "Wasser", "Wind", "Eis", "Blitz", "Licht", "Dunkelheit"},
+}
+
+var (
+	localeMu      sync.RWMutex
+	localeTags    []language.Tag
+	localeCatalog []Catalog
+	localeMatcher language.Matcher
+)
+
+func init() {
+	RegisterLocale(language.English, english)
+	RegisterLocale(language.Japanese, japanese)
+	RegisterLocale(language.French, french)
+	RegisterLocale(language.German, german)
+}
+
+// RegisterLocale registers the catalog c for the locale tag, replacing the
+// catalog previously registered for the same tag. English, Japanese, French
+// and German are registered by default.
+//
+// The registered locales are used by the StringLocale methods and
+// StrftimeLocale, which select the best match for the requested locale and
+// fall back to English, and by Strptime, which accepts weekday names in any
+// registered locale.
+func RegisterLocale(tag language.Tag, c Catalog) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+
+	for i, t := range localeTags {
+		if t == tag {
+			localeCatalog[i] = c
+			return
+		}
+	}
+	localeTags = append(localeTags, tag)
+	localeCatalog = append(localeCatalog, c)
+	localeMatcher = language.NewMatcher(localeTags)
+}
+
+// lookupLocale returns the catalog which best matches locale.
+func lookupLocale(locale string) *Catalog {
+	userTag := language.Make(locale)
+
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+
+	// the first registered locale (English) is the default
+	_, i, _ := localeMatcher.Match(userTag)
+	c := localeCatalog[i]
+	return &c
+}
+
+// locales returns all the registered catalogs.
+func locales() []Catalog {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+
+	return append([]Catalog(nil), localeCatalog...)
+}
@@ -0,0 +1,126 @@
+package vanatime_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"golang.org/x/text/language"
+)
+
+func TestStringLocale(t *testing.T) {
+	// 1300-02-03 is Firesday, Waning Gibbous (76%)
+	vt := vanatime.Date(1300, 2, 3, 0, 0, 0, 0)
+	patterns := []struct {
+		Locale  string
+		Weekday string
+		Abbr    string
+		Moon    string
+		Element string
+	}{
+		{"en", "Firesday", "Fir", "Waning Gibbous", "Fire"},
+		{"en-GB", "Firesday", "Fir", "Waning Gibbous", "Fire"},
+		{"ja", "火曜日", "火", "居待月", "火"},
+		{"fr", "Jour de feu", "Feu", "Gibbeuse décroissante", "Feu"},
+		{"fr-CA", "Jour de feu", "Feu", "Gibbeuse décroissante", "Feu"},
+		{"de", "Feuertag", "Feu", "Abnehmender Mond", "Feuer"},
+		{"ko", "Firesday", "Fir", "Waning Gibbous", "Fire"},
+		{"", "Firesday", "Fir", "Waning Gibbous", "Fire"},
+	}
+
+	for i, pattern := range patterns {
+		w := vt.Weekday()
+		if got := w.StringLocale(pattern.Locale); got != pattern.Weekday {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Weekday, got)
+		}
+		if got := w.AbbrLocale(pattern.Locale); got != pattern.Abbr {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Abbr, got)
+		}
+		if got := vt.Moon().Phase().StringLocale(pattern.Locale); got != pattern.Moon {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Moon, got)
+		}
+		if got := w.Element().StringLocale(pattern.Locale); got != pattern.Element {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Element, got)
+		}
+	}
+
+	if got, want := vt.StringLocale("de"), "1300-02-03 00:00:00 Feuertag Abnehmender Mond (76%)"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+}
+
+func TestStrftimeLocale(t *testing.T) {
+	vt := vanatime.Date(1300, 2, 10, 0, 0, 0, 0)
+	patterns := []struct {
+		Format string
+		Locale string
+		Want   string
+	}{
+		{"%A %a", "en", "Darksday Drk"},
+		{"%A %a", "ja", "闇曜日 闇"},
+		{"%A (%a)", "fr", "Jour des ténèbres (Tén)"},
+		{"%^A", "fr", "JOUR DES TÉNÈBRES"},
+		{"%F %A", "de", "1300-02-10 Dunkeltag"},
+		{"%10a|", "de", "       Dun|"},
+	}
+
+	for i, pattern := range patterns {
+		if got := vt.StrftimeLocale(pattern.Format, pattern.Locale); got != pattern.Want {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want, got)
+		}
+	}
+	if got := vt.Strftime("%a"); got != "Drk" {
+		t.Errorf(`want "Drk", but "%s"`, got)
+	}
+}
+
+func TestStrptimeLocale(t *testing.T) {
+	want := vanatime.Date(1300, 2, 10, 0, 0, 0, 0)
+	patterns := []struct {
+		Format string
+		Value  string
+	}{
+		{"%F %A", "1300-02-10 Darksday"},
+		{"%F %A", "1300-02-10 闇曜日"},
+		{"%F %A", "1300-02-10 Jour des ténèbres"},
+		{"%F %A", "1300-02-10 dunkeltag"},
+		{"%F %a", "1300-02-10 Drk"},
+		{"%a %F", "Tén 1300-02-10"},
+	}
+
+	for i, pattern := range patterns {
+		got, err := vanatime.Strptime(pattern.Format, pattern.Value)
+		if err != nil {
+			t.Errorf("[%d]: %v", i, err)
+		} else if !got.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	es := vanatime.Catalog{
+		Weekdays:     [8]string{"Día de fuego", "Día de tierra", "Día de agua", "Día de viento", "Día de hielo", "Día de rayo", "Día de luz", "Día de oscuridad"},
+		WeekdayAbbrs: [8]string{"Fue", "Tie", "Agu", "Vie", "Hie", "Ray", "Luz", "Osc"},
+		MoonPhases:   [12]string{"Luna nueva"},
+		Elements:     [8]string{"Fuego", "Tierra", "Agua", "Viento", "Hielo", "Rayo", "Luz", "Oscuridad"},
+	}
+	vanatime.RegisterLocale(language.Spanish, es)
+
+	vt := vanatime.Date(1300, 2, 6, 0, 0, 0, 0)
+	if got, want := vt.StrftimeLocale("%A", "es-MX"), "Día de viento"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+	if got, want := vanatime.NewMoon.StringLocale("es"), "Luna nueva"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+	if _, err := vanatime.Strptime("%F %A", "1300-02-06 día de viento"); err != nil {
+		t.Error(err)
+	}
+
+	// registering again replaces the catalog
+	es.Weekdays[3] = "Viento"
+	vanatime.RegisterLocale(language.Spanish, es)
+	if got, want := vt.Weekday().StringLocale("es"), "Viento"; got != want {
+		t.Errorf(`want "%s", but "%s"`, want, got)
+	}
+}
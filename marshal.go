@@ -0,0 +1,123 @@
+package vanatime
+
+import (
+	"errors"
+	"strconv"
+)
+
+const timeBinaryVersion byte = 1
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t Time) MarshalBinary() ([]byte, error) {
+	enc := []byte{
+		timeBinaryVersion,  // byte 0 : version
+		byte(t.time >> 56), // bytes 1-8: microseconds
+		byte(t.time >> 48),
+		byte(t.time >> 40),
+		byte(t.time >> 32),
+		byte(t.time >> 24),
+		byte(t.time >> 16),
+		byte(t.time >> 8),
+		byte(t.time),
+	}
+	return enc, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *Time) UnmarshalBinary(data []byte) error {
+	buf := data
+	if len(buf) == 0 {
+		return errors.New("vanatime: Time.UnmarshalBinary: no data")
+	}
+
+	if buf[0] != timeBinaryVersion {
+		return errors.New("vanatime: Time.UnmarshalBinary: unsupported version")
+	}
+
+	if len(buf) != /*version*/ 1+ /*usec*/ 8 {
+		return errors.New("vanatime: Time.UnmarshalBinary: invalid length")
+	}
+
+	buf = buf[1:]
+	t.time = int64(buf[7]) | int64(buf[6])<<8 | int64(buf[5])<<16 | int64(buf[4])<<24 |
+		int64(buf[3])<<32 | int64(buf[2])<<40 | int64(buf[1])<<48 | int64(buf[0])<<56
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (t Time) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (t *Time) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The time is a quoted string in the DateTimeMicro format.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.Format(DateTimeMicro))), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The time is expected to be a quoted string in the DateTimeMicro format.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	// Ignore null, like in the main JSON package.
+	if string(data) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return errors.New("vanatime: Time.UnmarshalJSON: input is not a JSON string")
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The time is formatted in the DateTimeMicro format.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.Format(DateTimeMicro)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The time is expected to be in the DateTimeMicro format.
+func (t *Time) UnmarshalText(data []byte) error {
+	var err error
+	*t, err = Parse(DateTimeMicro, string(data))
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The duration is a quoted string in the form returned by Duration.String.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The duration is expected to be a quoted string accepted by ParseDuration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	// Ignore null, like in the main JSON package.
+	if string(data) == "null" {
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return errors.New("vanatime: Duration.UnmarshalJSON: input is not a JSON string")
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The duration is formatted as by Duration.String.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The duration is expected to be a string accepted by ParseDuration.
+func (d *Duration) UnmarshalText(data []byte) error {
+	var err error
+	*d, err = ParseDuration(string(data))
+	return err
+}
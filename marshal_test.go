@@ -0,0 +1,180 @@
+package vanatime_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+var marshalTimes = []vanatime.Time{
+	vanatime.Date(1, 1, 1, 0, 0, 0, 0),
+	vanatime.Date(1313, 4, 13, 21, 20, 27, 654321),
+	vanatime.Date(886, 12, 30, 23, 59, 59, 999999),
+	vanatime.Date(0, 6, 15, 12, 0, 0, 1),
+	vanatime.Date(-1, 2, 3, 4, 5, 6, 7),
+	vanatime.Date(-1300, 12, 30, 23, 59, 59, 999999),
+}
+
+func TestTimeMarshalText(t *testing.T) {
+	patterns := []struct {
+		T    vanatime.Time
+		Want string
+	}{
+		{vanatime.Date(1313, 4, 13, 21, 20, 27, 654321), "1313-04-13 21:20:27.654321"},
+		{vanatime.Date(-1, 2, 3, 4, 5, 6, 7), "-0001-02-03 04:05:06.000007"},
+	}
+
+	for i, pattern := range patterns {
+		got, err := pattern.T.MarshalText()
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if string(got) != pattern.Want {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want, got)
+		}
+	}
+}
+
+func TestTimeTextRoundTrip(t *testing.T) {
+	for i, vt := range marshalTimes {
+		b, err := vt.MarshalText()
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		var got vanatime.Time
+		if err := got.UnmarshalText(b); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.Equal(vt) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, vt, got)
+		}
+	}
+}
+
+func TestTimeJSONRoundTrip(t *testing.T) {
+	type event struct {
+		Name string
+		At   vanatime.Time
+	}
+
+	for i, vt := range marshalTimes {
+		b, err := json.Marshal(event{"NM", vt})
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		var got event
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.At.Equal(vt) {
+			t.Errorf(`[%d]: want "%v", but "%v" (%s)`, i, vt, got.At, b)
+		}
+	}
+}
+
+func TestTimeJSONError(t *testing.T) {
+	inputs := []string{
+		`1300`,
+		`"1300-02-03"`,
+		`"1300-13-03 00:00:00.000000"`,
+	}
+
+	for i, input := range inputs {
+		var vt vanatime.Time
+		if err := json.Unmarshal([]byte(input), &vt); err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+		}
+	}
+}
+
+func TestTimeBinaryRoundTrip(t *testing.T) {
+	for i, vt := range marshalTimes {
+		b, err := vt.MarshalBinary()
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		var got vanatime.Time
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.Equal(vt) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, vt, got)
+		}
+	}
+}
+
+func TestTimeUnmarshalBinaryError(t *testing.T) {
+	inputs := [][]byte{
+		nil,
+		{2, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0, 0, 0},
+	}
+
+	for i, input := range inputs {
+		var vt vanatime.Time
+		if err := vt.UnmarshalBinary(input); err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+		}
+	}
+}
+
+func TestTimeGobRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	dec := gob.NewDecoder(&buf)
+
+	for i, vt := range marshalTimes {
+		if err := enc.Encode(vt); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		var got vanatime.Time
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.Equal(vt) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, vt, got)
+		}
+	}
+}
+
+func TestDurationJSONRoundTrip(t *testing.T) {
+	durations := []vanatime.Duration{
+		0,
+		vanatime.Microsecond,
+		1500 * vanatime.Millisecond,
+		-3*vanatime.Hour - 30*vanatime.Minute,
+		vanatime.Year + vanatime.Day + 123*vanatime.Microsecond,
+		// beyond the range of time.Duration
+		vanatime.Date(1313, 1, 1, 0, 0, 0, 0).Sub(vanatime.Date(1, 1, 1, 0, 0, 0, 0)),
+		-vanatime.Date(1313, 4, 13, 21, 20, 27, 654321).Sub(vanatime.Date(1, 1, 1, 0, 0, 0, 0)),
+		1<<63 - 1,
+		-1 << 63,
+	}
+
+	for i, d := range durations {
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if want := `"` + d.String() + `"`; string(b) != want {
+			t.Errorf(`[%d]: want %s, but %s`, i, want, b)
+		}
+		var got vanatime.Duration
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if got != d {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, d, got)
+		}
+	}
+}
+
+func TestDurationUnmarshalTextError(t *testing.T) {
+	var d vanatime.Duration
+	if err := d.UnmarshalText([]byte("1ns")); err == nil {
+		t.Errorf("want error, but nil")
+	}
+}
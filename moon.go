@@ -3,8 +3,7 @@ package vanatime
 import (
 	"fmt"
 	"math"
-
-	"golang.org/x/text/language"
+	"time"
 )
 
 // MOON_BASE_TIME  = 0 - (ONE_DAY * 12) # Start of New moon (10%)
@@ -90,81 +89,170 @@ var defaultMoonNames = [...]string{
 	"Waning Crescent",
 }
 
-var moonNames = map[language.Tag][12]string{
-	language.English: [12]string{
-		"New Moon",
-		"Waxing Crescent",
-		"Waxing Crescent",
-		"First Quarter",
-		"Waxing Gibbous",
-		"Waxing Gibbous",
-		"Full Moon",
-		"Waning Gibbous",
-		"Waning Gibbous",
-		"Last Quarter",
-		"Waning Crescent",
-		"Waning Crescent",
-	},
-	language.Japanese: [12]string{
-		"新月",
-		"三日月",
-		"七日月",
-		"上弦の月",
-		"十日夜",
-		"十三夜",
-		"満月",
-		"十六夜",
-		"居待月",
-		"下弦の月",
-		"二十日余月",
-		"二十六夜",
-	},
-}
-
-var moonLangs language.Matcher
-
-func init() {
-	var keys []language.Tag
-	for k, _ := range moonNames {
-		keys = append(keys, k)
-	}
-	moonLangs = language.NewMatcher(keys)
-}
-
 func (m MoonPhase) String() string {
 	return defaultMoonNames[m]
 }
 
 func (m MoonPhase) StringLocale(locale string) string {
-	userTag := language.Make(locale)
-	tag, _, _ := moonLangs.Match(userTag)
-	if names, ok := moonNames[tag]; ok {
-		return names[m]
-	}
-	return m.String()
+	return lookupLocale(locale).MoonPhases[m]
 }
 
+// A Moon represents the moon at an instant.
 type Moon struct {
 	days       int
 	timeOfMoon int64
 }
 
+// Percent returns the moon percent (0 to 100). It changes at 00:00 every
+// day, following the table at the top of this file.
 func (m Moon) Percent() int {
-	percent := math.Round(float64((m.days+8)%MoonCycleDays) * (200.0 / float64(MoonCycleDays)))
+	percent := math.Round(float64(m.Age()) * (200.0 / float64(MoonCycleDays)))
 	if percent > 100.0 {
 		percent = 200.0 - percent
 	}
 	return int(percent)
 }
 
+// PercentFloat returns the moon percent (0 to 100) as a continuous value
+// which also changes during the day. At 00:00 it rounds to Percent.
+func (m Moon) PercentFloat() float64 {
+	age := float64(m.Age()) + float64(m.timeOfMoon%int64(Day))/float64(Day)
+	percent := age * (200.0 / float64(MoonCycleDays))
+	if percent > 100.0 {
+		percent = 200.0 - percent
+	}
+	return percent
+}
+
+// Age returns the day within the moon cycle, in the range [0, 83].
+// Day 0 is the 0% New Moon and day 42 is the 100% Full Moon.
+func (m Moon) Age() int {
+	return int(floorMod(int64(m.days)+8, int64(MoonCycleDays)))
+}
+
+// Waxing reports whether the moon is waxing, that is, whether the percent
+// increases from the New Moon (0%) to the Full Moon (100%).
+func (m Moon) Waxing() bool {
+	return m.Age() < MoonCycleDays/2
+}
+
+// Cycle returns the number of the moon cycle, counting the cycles which
+// start at the 0% New Moon. C.E. 0001-01-01 is in cycle 0.
+func (m Moon) Cycle() int {
+	return int(floorDiv(int64(m.days)+8, int64(MoonCycleDays)))
+}
+
+// Phase returns the moon phase.
 func (m Moon) Phase() MoonPhase {
-	return MoonPhase(((m.days + 12) / 7) % 12)
+	return MoonPhase(floorMod(floorDiv(int64(m.days)+12, phaseDays), 12))
 }
 
-func (m Moon) TimeOfMoon() int64 {
-	return m.timeOfMoon
+// TimeOfMoon returns the time elapsed since the current moon phase began,
+// in the range [0, 7 days).
+func (m Moon) TimeOfMoon() Duration {
+	return Duration(m.timeOfMoon)
 }
 
 func (m Moon) String() string {
 	return fmt.Sprintf("%s (%d%%)", m.Phase(), m.Percent())
 }
+
+// StringLocale returns the moon phase name by specified locale followed by
+// the percent.
+func (m Moon) StringLocale(locale string) string {
+	return fmt.Sprintf("%s (%d%%)", m.Phase().StringLocale(locale), m.Percent())
+}
+
+// moon phases change every 7 days
+const phaseDays = 7
+
+// PhaseStart returns the time at which the moon phase in effect at t began.
+// Moon phases change at 00:00 every 7 days.
+func PhaseStart(t Time) Time {
+	day := floorDiv(t.time, int64(Day))
+	day -= floorMod(day+12, phaseDays)
+	return Time{day * int64(Day)}
+}
+
+// PhaseEnd returns the time at which the moon phase in effect at t ends,
+// that is, the start of the following phase.
+func PhaseEnd(t Time) Time {
+	return PhaseStart(t).Add(phaseDays * Day)
+}
+
+// NextPhase returns the time at which the next moon phase p after t begins.
+func NextPhase(t Time, p MoonPhase) Time {
+	start := PhaseEnd(t)
+	for i := 0; i < 12 && start.Moon().Phase() != p; i++ {
+		start = start.Add(phaseDays * Day)
+	}
+	return start
+}
+
+// NextPercent returns the start of the first day after t on which the moon
+// percent is pct. The percent changes at 00:00 every day and not every
+// value from 0 to 100 occurs; ok is false if pct never occurs.
+func NextPercent(t Time, pct int) (next Time, ok bool) {
+	day := t.Truncate(Day).Add(Day)
+	for i := 0; i < MoonCycleDays; i++ {
+		if day.Moon().Percent() == pct {
+			return day, true
+		}
+		day = day.Add(Day)
+	}
+	return Time{}, false
+}
+
+// A PhaseTransition is the start of a moon phase.
+type PhaseTransition struct {
+	Time  Time
+	Phase MoonPhase
+}
+
+// Earth returns the Earth time of the transition.
+func (p PhaseTransition) Earth() time.Time {
+	return p.Time.Earth()
+}
+
+// A PhaseIter iterates over the moon phase transitions in a time range.
+//
+//     it := vanatime.PhaseTransitions(start, end)
+//     for it.Next() {
+//         tr := it.Transition()
+//         ...
+//     }
+type PhaseIter struct {
+	next Time
+	end  Time
+	cur  PhaseTransition
+}
+
+// PhaseTransitions returns an iterator over the moon phase transitions
+// which occur in the range [start, end).
+func PhaseTransitions(start, end Time) *PhaseIter {
+	next := PhaseStart(start)
+	if next.Before(start) {
+		next = next.Add(phaseDays * Day)
+	}
+	return &PhaseIter{next: next, end: end}
+}
+
+// Next advances the iterator to the next transition, which will then be
+// available through the Transition method. It returns false when there are
+// no more transitions in the range.
+func (it *PhaseIter) Next() bool {
+	if !it.next.Before(it.end) {
+		return false
+	}
+	it.cur = PhaseTransition{
+		Time:  it.next,
+		Phase: it.next.Moon().Phase(),
+	}
+	it.next = it.next.Add(phaseDays * Day)
+	return true
+}
+
+// Transition returns the current transition.
+func (it *PhaseIter) Transition() PhaseTransition {
+	return it.cur
+}
@@ -0,0 +1,223 @@
+package vanatime_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func TestPhaseStartEnd(t *testing.T) {
+	// C.E. 0001-02-03 is in the Full Moon from 0001-02-01 to 0001-02-07
+	patterns := []vanatime.Time{
+		vanatime.Date(1, 2, 1, 0, 0, 0, 0),
+		vanatime.Date(1, 2, 3, 4, 5, 6, 0),
+		vanatime.Date(1, 2, 7, 23, 59, 59, 999999),
+	}
+
+	for i, vt := range patterns {
+		if got, want := vanatime.PhaseStart(vt), vanatime.Date(1, 2, 1, 0, 0, 0, 0); !got.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+		if got, want := vanatime.PhaseEnd(vt), vanatime.Date(1, 2, 8, 0, 0, 0, 0); !got.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+	}
+}
+
+func TestPhaseBoundaries(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	for i := 0; i < 24; i++ {
+		start, end := vanatime.PhaseStart(vt), vanatime.PhaseEnd(vt)
+		if start.After(vt) || !end.After(vt) || end.Sub(start) != 7*vanatime.Day {
+			t.Fatalf(`[%d]: "%v" is not in ["%v", "%v")`, i, vt, start, end)
+		}
+		phase := vt.Moon().Phase()
+		if start.Moon().Phase() != phase || end.Add(-1).Moon().Phase() != phase {
+			t.Fatalf(`[%d]: phase of "%v" is not constant in ["%v", "%v")`, i, vt, start, end)
+		}
+		if start.Add(-1).Moon().Phase() == phase || end.Moon().Phase() == phase {
+			t.Fatalf(`[%d]: phase of "%v" does not change at "%v" or "%v"`, i, vt, start, end)
+		}
+		vt = end.Add(3*vanatime.Day + 5*vanatime.Hour)
+	}
+}
+
+func TestNextPhase(t *testing.T) {
+	vt := vanatime.Date(1, 2, 3, 4, 5, 6, 0)
+	patterns := []struct {
+		P    vanatime.MoonPhase
+		Want vanatime.Time
+	}{
+		{vanatime.WaningGibbous1, vanatime.Date(1, 2, 8, 0, 0, 0, 0)},
+		{vanatime.NewMoon, vanatime.Date(1, 3, 13, 0, 0, 0, 0)},
+		{vanatime.FullMoon, vanatime.Date(1, 4, 25, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got := vanatime.NextPhase(vt, pattern.P)
+		if !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+		if got.Moon().Phase() != pattern.P {
+			t.Errorf(`[%d]: want %v, but %v`, i, pattern.P, got.Moon().Phase())
+		}
+	}
+}
+
+func TestNextPercent(t *testing.T) {
+	vt := vanatime.Date(1, 2, 3, 4, 5, 6, 0)
+	patterns := []struct {
+		Pct  int
+		Want vanatime.Time
+		OK   bool
+	}{
+		{100, vanatime.Date(1, 2, 5, 0, 0, 0, 0), true},
+		{95, vanatime.Date(1, 2, 7, 0, 0, 0, 0), true},
+		{0, vanatime.Date(1, 3, 17, 0, 0, 0, 0), true},
+		{1, vanatime.Time{}, false},
+	}
+
+	for i, pattern := range patterns {
+		got, ok := vanatime.NextPercent(vt, pattern.Pct)
+		if ok != pattern.OK || !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v" (%v), but "%v" (%v)`, i, pattern.Want, pattern.OK, got, ok)
+		}
+		if ok && got.Moon().Percent() != pattern.Pct {
+			t.Errorf(`[%d]: want %d%%, but %d%%`, i, pattern.Pct, got.Moon().Percent())
+		}
+	}
+}
+
+func TestPhaseTransitions(t *testing.T) {
+	start := vanatime.Date(1, 2, 1, 0, 0, 0, 0)
+	end := start.Add(vanatime.Duration(vanatime.MoonCycleDays) * vanatime.Day)
+
+	it := vanatime.PhaseTransitions(start, end)
+	var got []vanatime.PhaseTransition
+	for it.Next() {
+		got = append(got, it.Transition())
+	}
+
+	if len(got) != 12 {
+		t.Fatalf("want 12 transitions, but %d", len(got))
+	}
+	for i, tr := range got {
+		want := start.Add(vanatime.Duration(i*7) * vanatime.Day)
+		if !tr.Time.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, tr.Time)
+		}
+		if wantPhase := vanatime.MoonPhase((int(vanatime.FullMoon) + i) % 12); tr.Phase != wantPhase {
+			t.Errorf(`[%d]: want %v, but %v`, i, wantPhase, tr.Phase)
+		}
+		if !tr.Earth().Equal(want.Earth()) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want.Earth(), tr.Earth())
+		}
+	}
+
+	// a range starting in the middle of a phase
+	it = vanatime.PhaseTransitions(start.Add(1), start.Add(7*vanatime.Day+1))
+	if !it.Next() || !it.Transition().Time.Equal(start.Add(7*vanatime.Day)) {
+		t.Errorf(`want "%v", but "%v"`, start.Add(7*vanatime.Day), it.Transition().Time)
+	}
+	if it.Next() {
+		t.Errorf(`unexpected transition "%v"`, it.Transition().Time)
+	}
+}
+
+// moonTable is the percent table at the top of moon.go, indexed by the age
+// of the moon.
+var moonTable = []struct {
+	Phase   vanatime.MoonPhase
+	Percent []int
+}{
+	{vanatime.NewMoon, []int{0, 2, 5}},
+	{vanatime.WaxingCrescent1, []int{7, 10, 12, 14, 17, 19, 21}},
+	{vanatime.WaxingCrescent2, []int{24, 26, 29, 31, 33, 36, 38}},
+	{vanatime.FirstQuarter, []int{40, 43, 45, 48, 50, 52, 55}},
+	{vanatime.WaxingGibbous1, []int{57, 60, 62, 64, 67, 69, 71}},
+	{vanatime.WaxingGibbous2, []int{74, 76, 79, 81, 83, 86, 88}},
+	{vanatime.FullMoon, []int{90, 93, 95, 98, 100, 98, 95}},
+	{vanatime.WaningGibbous1, []int{93, 90, 88, 86, 83, 81, 79}},
+	{vanatime.WaningGibbous2, []int{76, 74, 71, 69, 67, 64, 62}},
+	{vanatime.LastQuarter, []int{60, 57, 55, 52, 50, 48, 45}},
+	{vanatime.WaningCrescent1, []int{43, 40, 38, 36, 33, 31, 29}},
+	{vanatime.WaningCrescent2, []int{26, 24, 21, 19, 17, 14, 12}},
+	{vanatime.NewMoon, []int{10, 7, 5, 2}},
+}
+
+func TestMoonTable(t *testing.T) {
+	// C.E. 0001-01-01 is the 8th day of the cycle 0 (19%)
+	for _, cycle := range []int{-2, 0, 1, 15} {
+		day := vanatime.Date(1, 1, 1, 0, 0, 0, 0).Add(vanatime.Duration(cycle*vanatime.MoonCycleDays-8) * vanatime.Day)
+		age := 0
+		for _, col := range moonTable {
+			for _, pct := range col.Percent {
+				for _, vt := range []vanatime.Time{day, day.Add(vanatime.Day - 1)} {
+					m := vt.Moon()
+					if m.Percent() != pct || m.Phase() != col.Phase {
+						t.Errorf(`"%v": want %v (%d%%), but %v (%d%%)`, vt, col.Phase, pct, m.Phase(), m.Percent())
+					}
+					if m.Age() != age {
+						t.Errorf(`"%v": want age %d, but %d`, vt, age, m.Age())
+					}
+					if m.Cycle() != cycle {
+						t.Errorf(`"%v": want cycle %d, but %d`, vt, cycle, m.Cycle())
+					}
+					if want := age < 42; m.Waxing() != want {
+						t.Errorf(`"%v": want waxing %v, but %v`, vt, want, m.Waxing())
+					}
+				}
+				if got := int(math.Round(day.Moon().PercentFloat())); got != pct {
+					t.Errorf(`"%v": want %d%%, but %d%%`, day, pct, got)
+				}
+				day = day.Add(vanatime.Day)
+				age++
+			}
+		}
+	}
+}
+
+func TestMoonPercentFloat(t *testing.T) {
+	// 0001-02-05 is the 100% Full Moon
+	full := vanatime.Date(1, 2, 5, 0, 0, 0, 0)
+	step := 200.0 / float64(vanatime.MoonCycleDays)
+	patterns := []struct {
+		T    vanatime.Time
+		Want float64
+	}{
+		{full, 100},
+		{full.Add(-vanatime.Day), 100 - step},
+		{full.Add(-vanatime.Day / 2), 100 - step/2},
+		{full.Add(vanatime.Day / 4), 100 - step/4},
+		{full.Add(41 * vanatime.Day), step},
+		{full.Add(42 * vanatime.Day), 0},
+		{full.Add(42*vanatime.Day + vanatime.Day/2), step / 2},
+	}
+
+	for i, pattern := range patterns {
+		if got := pattern.T.Moon().PercentFloat(); math.Abs(got-pattern.Want) > 1e-9 {
+			t.Errorf(`[%d]: "%v": want %v, but %v`, i, pattern.T, pattern.Want, got)
+		}
+	}
+
+	// continuous and monotonic within the waxing and waning halves
+	prev := full.Moon().PercentFloat()
+	for vt := full.Add(vanatime.Hour); vt.Before(full.Add(42 * vanatime.Day)); vt = vt.Add(vanatime.Hour) {
+		got := vt.Moon().PercentFloat()
+		if got >= prev || prev-got > step/24+1e-9 {
+			t.Fatalf(`"%v": %v after %v`, vt, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestTimeOfMoon(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	for i := 0; i < 20; i++ {
+		if got, want := vt.Moon().TimeOfMoon(), vt.Sub(vanatime.PhaseStart(vt)); got != want {
+			t.Errorf(`"%v": want %v, but %v`, vt, want, got)
+		}
+		vt = vt.Add(-(vanatime.Day + 7*vanatime.Hour))
+	}
+}
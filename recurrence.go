@@ -0,0 +1,445 @@
+package vanatime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Frequency specifies the period of a Recurrence.
+type Frequency int
+
+const (
+	Hourly Frequency = iota + 1
+	Daily
+	Weekly // every 8 days, from Firesday
+	Monthly
+	Yearly
+)
+
+var frequencyNames = [...]string{
+	Hourly:  "HOURLY",
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+// String returns the name of the frequency in a recurrence rule
+// ("HOURLY", "DAILY", ...).
+func (f Frequency) String() string {
+	if f < Hourly || f > Yearly {
+		return "Frequency(" + strconv.Itoa(int(f)) + ")"
+	}
+	return frequencyNames[f]
+}
+
+// weekday codes in recurrence rules
+var weekdayCodes = [...]string{"FI", "EA", "WA", "WI", "IC", "LN", "LT", "DA"}
+
+// A WeekdayNum is a day of the week in the BYDAY part of a recurrence
+// rule, optionally with its position in the month or the year.
+type WeekdayNum struct {
+	// N is the position of the day in the month (MONTHLY) or the year
+	// (YEARLY): 1 is the first, 2 the second, -1 the last, and so on.
+	// 0 means every such day, and N is ignored by the other frequencies.
+	N       int
+	Weekday Weekday
+}
+
+// String returns the weekday as in a recurrence rule ("FI", "1FI", "-1DA").
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayCodes[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayCodes[w.Weekday]
+}
+
+// A Recurrence is a rule for recurring events over the Vana'diel calendar,
+// modeled after the recurrence rules of iCalendar (RFC 5545).
+//
+// The events occur every Interval periods of Freq from Start, at the time
+// of day of Start. ByDay and ByMonthDay select the days within each period:
+// for Weekly, Monthly and Yearly they list the days of the period on which
+// the event occurs, and for Hourly and Daily they only limit the occurrences
+// to those days. Without them, the event occurs on the same day of the
+// week, month or year as Start. Occurrences before Start are excluded.
+//
+// The recurrence ends after Count occurrences, or with the last occurrence
+// not after Until, if either is set.
+type Recurrence struct {
+	Start      Time
+	Freq       Frequency
+	Interval   int          // the number of periods between occurrences; 0 means 1
+	ByDay      []WeekdayNum // days of the week
+	ByMonthDay []int        // days of the month, 1 to 30, or -1 (the last day) to -30
+	Count      int          // the number of occurrences; 0 means unlimited
+	Until      Time         // the last time of occurrences; the zero Time means unlimited
+}
+
+// maxEmptyPeriods is the number of consecutive periods without an
+// occurrence after which a recurrence is considered to have no more
+// occurrences, such as the 30th Firesday of a month.
+const maxEmptyPeriods = 10000
+
+// Validate reports an error if the recurrence rule is invalid.
+func (r Recurrence) Validate() error {
+	if r.Freq < Hourly || r.Freq > Yearly {
+		return fmt.Errorf("vanatime: invalid recurrence frequency %d", r.Freq)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("vanatime: invalid recurrence interval %d", r.Interval)
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("vanatime: invalid recurrence count %d", r.Count)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("vanatime: recurrence with both count and until")
+	}
+	for _, w := range r.ByDay {
+		if w.Weekday < Firesday || w.Weekday > Darksday {
+			return fmt.Errorf("vanatime: invalid recurrence weekday %d", w.Weekday)
+		}
+		if w.N < -45 || w.N > 45 {
+			return fmt.Errorf("vanatime: invalid recurrence weekday position %d", w.N)
+		}
+	}
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -30 || d > 30 {
+			return fmt.Errorf("vanatime: invalid recurrence day of month %d", d)
+		}
+	}
+	return nil
+}
+
+// Iter returns an iterator over the occurrences of the recurrence.
+//
+//     it := r.Iter()
+//     for it.Next() {
+//         t := it.Time()
+//         ...
+//     }
+//
+// The iterator yields nothing if the rule is invalid.
+func (r Recurrence) Iter() *RecurrenceIter {
+	it := &RecurrenceIter{r: r}
+	if r.Interval == 0 {
+		it.r.Interval = 1
+	}
+	if r.Validate() != nil {
+		it.done = true
+	}
+	return it
+}
+
+// Between returns the occurrences of the recurrence in [start, end).
+func (r Recurrence) Between(start, end Time) []Time {
+	var times []Time
+	it := r.Iter()
+	for it.Next() {
+		t := it.Time()
+		if !t.Before(end) {
+			break
+		}
+		if !t.Before(start) {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// A RecurrenceIter iterates over the occurrences of a Recurrence.
+type RecurrenceIter struct {
+	r      Recurrence
+	period int64 // index of the next period
+	buf    []Time
+	count  int
+	cur    Time
+	done   bool
+}
+
+// Next advances the iterator to the next occurrence, which will then be
+// available through the Time method. It returns false when there are no
+// more occurrences.
+func (it *RecurrenceIter) Next() bool {
+	if it.done {
+		return false
+	}
+	for empty := 0; len(it.buf) == 0; empty++ {
+		if empty >= maxEmptyPeriods {
+			it.done = true
+			return false
+		}
+		for _, t := range it.r.expand(it.period) {
+			if !t.Before(it.r.Start) {
+				it.buf = append(it.buf, t)
+			}
+		}
+		it.period++
+	}
+
+	t := it.buf[0]
+	if (it.r.Count > 0 && it.count >= it.r.Count) || (!it.r.Until.IsZero() && t.After(it.r.Until)) {
+		it.done = true
+		return false
+	}
+	it.buf = it.buf[1:]
+	it.count++
+	it.cur = t
+	return true
+}
+
+// Time returns the current occurrence.
+func (it *RecurrenceIter) Time() Time {
+	return it.cur
+}
+
+// expand returns the occurrences in the n-th period, in order.
+func (r Recurrence) expand(n int64) []Time {
+	step := n * int64(r.Interval)
+	start := r.Start.time
+	tod := floorMod(start, int64(Day))
+
+	switch r.Freq {
+	case Hourly, Daily:
+		unit := int64(Hour)
+		if r.Freq == Daily {
+			unit = int64(Day)
+		}
+		t := Time{start + step*unit}
+		if r.limit(t) {
+			return []Time{t}
+		}
+		return nil
+
+	case Weekly:
+		week := floorDiv(start, int64(Week)) + step
+		return r.days(week*int64(Week), 8, tod)
+
+	case Monthly:
+		month := floorDiv(start, int64(Month)) + step
+		return r.days(month*int64(Month), 30, tod)
+
+	default:
+		year := floorDiv(start, int64(Year)) + step
+		return r.days(year*int64(Year), 360, tod)
+	}
+}
+
+// limit reports whether t is on one of the days of ByDay and ByMonthDay.
+func (r Recurrence) limit(t Time) bool {
+	if len(r.ByDay) > 0 {
+		ok := false
+		for _, w := range r.ByDay {
+			if w.Weekday == t.Weekday() {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return len(r.ByMonthDay) == 0 || r.matchMonthDay(t)
+}
+
+func (r Recurrence) matchMonthDay(t Time) bool {
+	_, _, day, _ := t.Date()
+	for _, d := range r.ByMonthDay {
+		if d == day || d == day-31 {
+			return true
+		}
+	}
+	return false
+}
+
+// days returns the occurrences on the days of the period of the given
+// number of days starting at base.
+func (r Recurrence) days(base int64, ndays int, tod int64) []Time {
+	startDay := Time{r.Start.time - floorMod(r.Start.time, int64(Day))}
+	_, _, startMDay, _ := r.Start.Date()
+
+	var times []Time
+	for i := 0; i < ndays; i++ {
+		day := Time{base + int64(i)*int64(Day)}
+		var ok bool
+		switch {
+		case len(r.ByDay) > 0 || len(r.ByMonthDay) > 0:
+			ok = (len(r.ByDay) == 0 || r.matchDay(day, i, ndays)) &&
+				(len(r.ByMonthDay) == 0 || r.matchMonthDay(day))
+		case r.Freq == Weekly:
+			ok = day.Weekday() == startDay.Weekday()
+		case r.Freq == Monthly:
+			_, _, mday, _ := day.Date()
+			ok = mday == startMDay
+		default:
+			ok = floorMod(day.time, int64(Year)) == floorMod(startDay.time, int64(Year))
+		}
+		if ok {
+			times = append(times, day.Add(Duration(tod)))
+		}
+	}
+	return times
+}
+
+// matchDay reports whether the i-th day of a period of ndays days matches
+// ByDay, with the positions counted within the period.
+func (r Recurrence) matchDay(day Time, i, ndays int) bool {
+	w := day.Weekday()
+	nth := i/8 + 1            // position of the day from the start
+	last := (ndays-1-i)/8 + 1 // position of the day from the end
+	for _, bd := range r.ByDay {
+		if bd.Weekday != w {
+			continue
+		}
+		if bd.N == 0 || r.Freq == Weekly || bd.N == nth || bd.N == -last {
+			return true
+		}
+	}
+	return false
+}
+
+// recurrenceLayout is the layout of DTSTART and UNTIL in the text form of a
+// recurrence, like the basic format of iCalendar.
+const recurrenceLayout = "00010203T040506.999999"
+
+// String returns the recurrence in the text form of a recurrence rule,
+// preceded by DTSTART, such as:
+//
+//     DTSTART=13130413T060000;FREQ=MONTHLY;BYDAY=1FI;COUNT=10
+func (r Recurrence) String() string {
+	parts := []string{
+		"DTSTART=" + r.Start.Format(recurrenceLayout),
+		"FREQ=" + r.Freq.String(),
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			days[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(recurrenceLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRecurrence parses a recurrence in the text form returned by String.
+// The parts are separated by semicolons and may appear in any order; FREQ
+// is required and DTSTART defaults to the zero Time.
+func ParseRecurrence(s string) (Recurrence, error) {
+	var r Recurrence
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Recurrence{}, fmt.Errorf("vanatime: invalid recurrence part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), kv[1]
+
+		var err error
+		switch key {
+		case "DTSTART":
+			r.Start, err = Parse(recurrenceLayout, value)
+		case "UNTIL":
+			r.Until, err = Parse(recurrenceLayout, value)
+		case "FREQ":
+			r.Freq = 0
+			for f := Hourly; f <= Yearly; f++ {
+				if strings.EqualFold(value, f.String()) {
+					r.Freq = f
+				}
+			}
+			if r.Freq == 0 {
+				err = fmt.Errorf("vanatime: invalid recurrence frequency %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay = nil
+			for _, v := range strings.Split(value, ",") {
+				var d int
+				if d, err = strconv.Atoi(v); err != nil {
+					break
+				}
+				r.ByMonthDay = append(r.ByMonthDay, d)
+			}
+		default:
+			err = fmt.Errorf("vanatime: unknown recurrence part %q", key)
+		}
+		if err != nil {
+			return Recurrence{}, err
+		}
+	}
+
+	if err := r.Validate(); err != nil {
+		return Recurrence{}, err
+	}
+	return r, nil
+}
+
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, v := range strings.Split(s, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("vanatime: invalid recurrence weekday %q", v)
+		}
+		code := strings.ToUpper(v[len(v)-2:])
+		w := -1
+		for i, c := range weekdayCodes {
+			if c == code {
+				w = i
+			}
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("vanatime: invalid recurrence weekday %q", v)
+		}
+		var n int
+		if num := v[:len(v)-2]; num != "" {
+			var err error
+			if n, err = strconv.Atoi(num); err != nil || n == 0 {
+				return nil, fmt.Errorf("vanatime: invalid recurrence weekday %q", v)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: Weekday(w)})
+	}
+	return days, nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The text is the form returned by String.
+func (r Recurrence) MarshalText() ([]byte, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The text is parsed by ParseRecurrence.
+func (r *Recurrence) UnmarshalText(data []byte) error {
+	v, err := ParseRecurrence(string(data))
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
@@ -0,0 +1,179 @@
+package vanatime_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func occurrences(r vanatime.Recurrence, max int) []vanatime.Time {
+	var times []vanatime.Time
+	it := r.Iter()
+	for len(times) < max && it.Next() {
+		times = append(times, it.Time())
+	}
+	return times
+}
+
+func TestRecurrence(t *testing.T) {
+	// 1313-04-13 is Lightsday, and 1313-04-15 is Firesday
+	start := vanatime.Date(1313, 4, 13, 6, 0, 0, 0)
+	d := func(mon, day, hour int) vanatime.Time {
+		return vanatime.Date(1313, mon, day, hour, 0, 0, 0)
+	}
+
+	patterns := []struct {
+		R    vanatime.Recurrence
+		Want []vanatime.Time
+	}{
+		// every 3rd day
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Daily, Interval: 3, Count: 4},
+			[]vanatime.Time{d(4, 13, 6), d(4, 16, 6), d(4, 19, 6), d(4, 22, 6)},
+		},
+		// the 1st Firesday of each month
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{1, vanatime.Firesday}}, Count: 4},
+			[]vanatime.Time{d(5, 1, 6), d(6, 3, 6), d(7, 5, 6), d(8, 7, 6)},
+		},
+		// the last Darksday of each month
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{-1, vanatime.Darksday}}, Count: 2},
+			[]vanatime.Time{d(4, 30, 6), d(5, 24, 6)},
+		},
+		// Firesday and Iceday every other week
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Weekly, Interval: 2, ByDay: []vanatime.WeekdayNum{{0, vanatime.Firesday}, {0, vanatime.Iceday}}, Count: 4},
+			[]vanatime.Time{d(4, 23, 6), d(4, 27, 6), d(5, 9, 6), d(5, 13, 6)},
+		},
+		// the same day of the week
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Weekly, Count: 3},
+			[]vanatime.Time{d(4, 13, 6), d(4, 21, 6), d(4, 29, 6)},
+		},
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Monthly, ByMonthDay: []int{-1, 15}, Until: d(5, 15, 6)},
+			[]vanatime.Time{d(4, 15, 6), d(4, 30, 6), d(5, 15, 6)},
+		},
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Yearly, Count: 3},
+			[]vanatime.Time{start, vanatime.Date(1314, 4, 13, 6, 0, 0, 0), vanatime.Date(1315, 4, 13, 6, 0, 0, 0)},
+		},
+		// every 5 hours on Darksday
+		{
+			vanatime.Recurrence{Start: d(4, 13, 21), Freq: vanatime.Hourly, Interval: 5, ByDay: []vanatime.WeekdayNum{{0, vanatime.Darksday}}, Until: d(4, 14, 20)},
+			[]vanatime.Time{d(4, 14, 2), d(4, 14, 7), d(4, 14, 12), d(4, 14, 17)},
+		},
+		// day 2 of a month is never Firesday
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{0, vanatime.Firesday}}, ByMonthDay: []int{2}},
+			nil,
+		},
+		// invalid
+		{
+			vanatime.Recurrence{Start: start, Freq: vanatime.Daily, ByMonthDay: []int{31}},
+			nil,
+		},
+	}
+
+	for i, pattern := range patterns {
+		got := occurrences(pattern.R, 10)
+		if len(got) != len(pattern.Want) {
+			t.Errorf("[%d]: %v: want %v, but %v", i, pattern.R, pattern.Want, got)
+			continue
+		}
+		for n := range got {
+			if !got[n].Equal(pattern.Want[n]) {
+				t.Errorf(`[%d]: %v: [%d]: want "%v", but "%v"`, i, pattern.R, n, pattern.Want[n], got[n])
+			}
+		}
+	}
+}
+
+func TestRecurrenceMoon(t *testing.T) {
+	// every 84 days starting at full moon
+	full := vanatime.NextPhase(vanatime.Date(1313, 4, 13, 0, 0, 0, 0), vanatime.FullMoon)
+	r := vanatime.Recurrence{Start: full, Freq: vanatime.Daily, Interval: vanatime.MoonCycleDays}
+	for i, vt := range occurrences(r, 5) {
+		if want := full.Add(vanatime.Duration(i*vanatime.MoonCycleDays) * vanatime.Day); !vt.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, vt)
+		}
+		if vt.Moon().Phase() != vanatime.FullMoon || vt.Moon().Age() != full.Moon().Age() {
+			t.Errorf(`[%d]: "%v" is not the start of the full moon`, i, vt)
+		}
+	}
+}
+
+func TestRecurrenceBetween(t *testing.T) {
+	r := vanatime.Recurrence{Start: vanatime.Date(1313, 4, 13, 6, 0, 0, 0), Freq: vanatime.Daily}
+	got := r.Between(vanatime.Date(1313, 4, 20, 6, 0, 0, 0), vanatime.Date(1313, 4, 23, 6, 0, 0, 0))
+	if len(got) != 3 || !got[0].Equal(vanatime.Date(1313, 4, 20, 6, 0, 0, 0)) || !got[2].Equal(vanatime.Date(1313, 4, 22, 6, 0, 0, 0)) {
+		t.Errorf("unexpected occurrences %v", got)
+	}
+}
+
+func TestRecurrenceText(t *testing.T) {
+	patterns := []struct {
+		R    vanatime.Recurrence
+		Text string
+	}{
+		{
+			vanatime.Recurrence{Start: vanatime.Date(1313, 4, 13, 6, 0, 0, 0), Freq: vanatime.Monthly, ByDay: []vanatime.WeekdayNum{{1, vanatime.Firesday}, {-1, vanatime.Darksday}}, Count: 10},
+			"DTSTART=13130413T060000;FREQ=MONTHLY;BYDAY=1FI,-1DA;COUNT=10",
+		},
+		{
+			vanatime.Recurrence{Start: vanatime.Date(-5, 1, 2, 3, 4, 5, 500000), Freq: vanatime.Weekly, Interval: 2, ByDay: []vanatime.WeekdayNum{{0, vanatime.Lightningday}, {0, vanatime.Lightsday}}, ByMonthDay: []int{1, -30}, Until: vanatime.Date(1, 1, 1, 12, 0, 0, 0)},
+			"DTSTART=-00050102T030405.5;FREQ=WEEKLY;INTERVAL=2;BYDAY=LN,LT;BYMONTHDAY=1,-30;UNTIL=00010101T120000",
+		},
+	}
+
+	for i, pattern := range patterns {
+		if got := pattern.R.String(); got != pattern.Text {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Text, got)
+		}
+		r, err := vanatime.ParseRecurrence(pattern.Text)
+		if err != nil {
+			t.Errorf("[%d]: %v", i, err)
+			continue
+		}
+		if got := r.String(); got != pattern.Text {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Text, got)
+		}
+	}
+
+	b, err := json.Marshal(patterns[0].R)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r vanatime.Recurrence
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.String() != patterns[0].Text {
+		t.Errorf(`want "%s", but "%s"`, patterns[0].Text, r.String())
+	}
+}
+
+func TestParseRecurrenceError(t *testing.T) {
+	patterns := []string{
+		"",
+		"DTSTART=13130413T060000",
+		"FREQ=SECONDLY",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;INTERVAL=-1",
+		"FREQ=DAILY;COUNT=3;UNTIL=13130413T060000",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=DAILY;BYDAY=0FI",
+		"FREQ=DAILY;BYMONTHDAY=0",
+		"FREQ=DAILY;BYMONTHDAY=31",
+		"FREQ=DAILY;UNTIL=1313-04-13",
+		"FREQ=DAILY;WKST=FI",
+	}
+
+	for i, s := range patterns {
+		if _, err := vanatime.ParseRecurrence(s); err == nil {
+			t.Errorf("[%d]: %q: want error, but nil", i, s)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Package schedule runs jobs on cron-like schedules expressed in Vana'diel
+// time, such as "every Lightsday at 06:00" or "every day at 20:00".
+//
+// A schedule expression consists of five or six fields separated by
+// spaces:
+//
+//     Field     Values
+//     -------   ----------------------------------------------
+//     minute    0-59
+//     hour      0-23
+//     day       1-30
+//     month     1-12
+//     weekday   0-7 or the name of the day or its element
+//               (Firesday or Fire = 0, ..., Darksday or Dark = 7)
+//     moon      0-11 or the name of the moon phase (NewMoon,
+//               WaxingCrescent1, ..., WaningCrescent2), or the moon
+//               percent followed by "%" (optional)
+//
+// Each field is "*" for any value, a value, a range "a-b", a step "*/n" or
+// "a-b/n", or a comma-separated list of them. Names are case-insensitive.
+// A time matches the expression if it matches every field; unlike cron, the
+// day and weekday fields are not combined with OR.
+//
+//     0 6 * * Lightsday       every Lightsday at 06:00
+//     0 20 * * *              every day at 20:00
+//     */30 * * * Fire,Ice     every 30 minutes on Firesday and Iceday
+//     0 0 * * * FullMoon      00:00 every day of the Full Moon
+//     0 18 * * * 90-100%      18:00 every day the moon is 90% or more
+//
+// The following descriptors may be used instead of the fields:
+//
+//     @hourly    0 * * * *
+//     @daily     0 0 * * *
+//     @weekly    0 0 * * Firesday
+//     @monthly   0 0 1 * *
+//     @yearly    0 0 1 1 *
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// A Schedule is a parsed schedule expression.
+type Schedule struct {
+	expr    string
+	minute  set
+	hour    set
+	day     set
+	month   set
+	weekday set
+	phase   set
+	percent set
+	anyMoon bool
+}
+
+// set is a set of small non-negative integers.
+type set [2]uint64
+
+func (s *set) add(i int)     { s[i/64] |= 1 << uint(i%64) }
+func (s set) has(i int) bool { return s[i/64]&(1<<uint(i%64)) != 0 }
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day", min: 1, max: 30}
+	monthField   = field{name: "month", min: 1, max: 12}
+	weekdayField = field{name: "weekday", min: 0, max: 7, names: map[string]int{}}
+	phaseField   = field{name: "moon", min: 0, max: 11, names: map[string]int{}}
+	percentField = field{name: "moon percent", min: 0, max: 100}
+)
+
+var phaseNames = [...]string{
+	"NewMoon",
+	"WaxingCrescent1",
+	"WaxingCrescent2",
+	"FirstQuarter",
+	"WaxingGibbous1",
+	"WaxingGibbous2",
+	"FullMoon",
+	"WaningGibbous1",
+	"WaningGibbous2",
+	"LastQuarter",
+	"WaningCrescent1",
+	"WaningCrescent2",
+}
+
+func init() {
+	for i := 0; i < 8; i++ {
+		w := vanatime.Weekday(i)
+		weekdayField.names[strings.ToLower(w.String())] = i
+		weekdayField.names[strings.ToLower(w.Element().String())] = i
+	}
+	for i, name := range phaseNames {
+		phaseField.names[strings.ToLower(name)] = i
+	}
+}
+
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * Firesday",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Parse parses a schedule expression.
+func Parse(expr string) (*Schedule, error) {
+	s := &Schedule{expr: expr}
+
+	spec := strings.TrimSpace(expr)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 && len(fields) != 6 {
+		return nil, fmt.Errorf("schedule: expected 5 or 6 fields, found %d in %q", len(fields), expr)
+	}
+
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.day, err = dayField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.weekday, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	s.anyMoon = true
+	if len(fields) == 6 && fields[5] != "*" {
+		s.anyMoon = false
+		for _, item := range strings.Split(fields[5], ",") {
+			if strings.HasSuffix(item, "%") {
+				err = percentField.parseItem(&s.percent, strings.TrimSuffix(item, "%"))
+			} else {
+				err = phaseField.parseItem(&s.phase, item)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed.
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (f field) parse(s string) (set, error) {
+	var v set
+	for _, item := range strings.Split(s, ",") {
+		if err := f.parseItem(&v, item); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+// parseItem parses one item of a list, "*", "a", "a-b", "*/n" or "a-b/n",
+// and adds its values to v.
+func (f field) parseItem(v *set, item string) error {
+	rng, step := item, 1
+	if i := strings.Index(item, "/"); i >= 0 {
+		n, err := strconv.Atoi(item[i+1:])
+		if err != nil || n <= 0 {
+			return f.errorf(item, "invalid step")
+		}
+		rng, step = item[:i], n
+	}
+
+	lo, hi := f.min, f.max
+	if rng != "*" {
+		var err error
+		bounds := strings.SplitN(rng, "-", 2)
+		if lo, err = f.value(bounds[0]); err != nil {
+			return f.errorf(item, err.Error())
+		}
+		hi = lo
+		if len(bounds) == 2 {
+			if hi, err = f.value(bounds[1]); err != nil {
+				return f.errorf(item, err.Error())
+			}
+		} else if step > 1 {
+			hi = f.max
+		}
+		if lo > hi {
+			return f.errorf(item, "invalid range")
+		}
+	}
+
+	for i := lo; i <= hi; i += step {
+		v.add(i)
+	}
+	return nil
+}
+
+func (f field) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, f.min, f.max)
+	}
+	return n, nil
+}
+
+func (f field) errorf(item, msg string) error {
+	return fmt.Errorf("schedule: %s in %s field %q", msg, f.name, item)
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// searchDays is the number of days searched by Next. Every combination of
+// day, month, weekday and moon repeats within it (the least common multiple
+// of a year, a week and a moon cycle).
+const searchDays = 2520
+
+// Match reports whether t, truncated to the minute, matches the schedule.
+func (s *Schedule) Match(t vanatime.Time) bool {
+	return s.matchDay(t) && s.hour.has(t.Hour()) && s.minute.has(t.Minute())
+}
+
+func (s *Schedule) matchDay(t vanatime.Time) bool {
+	_, mon, day, _ := t.Date()
+	if !s.month.has(mon) || !s.day.has(day) || !s.weekday.has(int(t.Weekday())) {
+		return false
+	}
+	if s.anyMoon {
+		return true
+	}
+	m := t.Moon()
+	return s.phase.has(int(m.Phase())) || s.percent.has(m.Percent())
+}
+
+// Next returns the first time after t which matches the schedule. The
+// times which match are always at the start of a Vana'diel minute. ok is
+// false if no time matches.
+func (s *Schedule) Next(t vanatime.Time) (next vanatime.Time, ok bool) {
+	start := t.Truncate(vanatime.Minute).Add(vanatime.Minute)
+	day := start.Truncate(vanatime.Day)
+	from := int(start.Sub(day) / vanatime.Minute)
+	for i := 0; i <= searchDays; i++ {
+		if s.matchDay(day) {
+			for m := from; m < 24*60; m++ {
+				if s.hour.has(m/60) && s.minute.has(m%60) {
+					return day.Add(vanatime.Duration(m) * vanatime.Minute), true
+				}
+			}
+		}
+		day = day.Add(vanatime.Day)
+		from = 0
+	}
+	return vanatime.Time{}, false
+}
+
+// NextN returns the first n times after t which match the schedule. It
+// returns fewer times if no more times match, and nil if n <= 0.
+func (s *Schedule) NextN(t vanatime.Time, n int) []vanatime.Time {
+	if n <= 0 {
+		return nil
+	}
+	times := make([]vanatime.Time, 0, n)
+	for len(times) < n {
+		var ok bool
+		if t, ok = s.Next(t); !ok {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
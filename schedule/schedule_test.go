@@ -0,0 +1,108 @@
+package schedule_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/schedule"
+)
+
+// 1313-04-13 is Lightsday, Waxing Crescent (33%)
+var base = vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+
+func TestNext(t *testing.T) {
+	full := vanatime.NextPhase(base, vanatime.FullMoon)
+	pct90, _ := vanatime.NextPercent(base, 90)
+	pct100, _ := vanatime.NextPercent(base, 100)
+
+	patterns := []struct {
+		Expr string
+		Want vanatime.Time
+		OK   bool
+	}{
+		{"0 6 * * Lightsday", vanatime.Date(1313, 4, 21, 6, 0, 0, 0), true},
+		{"0 20 * * *", vanatime.Date(1313, 4, 14, 20, 0, 0, 0), true},
+		{"21 21 * * *", vanatime.Date(1313, 4, 13, 21, 21, 0, 0), true},
+		{"20 21 * * *", vanatime.Date(1313, 4, 14, 21, 20, 0, 0), true},
+		{"*/30 * * * Fire,ice", vanatime.Date(1313, 4, 15, 0, 0, 0, 0), true},
+		{"15-45/15 3 * * 3-4", vanatime.Date(1313, 4, 18, 3, 15, 0, 0), true},
+		{"0 0 * * * FullMoon", full, true},
+		{"0 18 * * * 90-100%", pct90.Add(18 * vanatime.Hour), true},
+		{"0 0 * * * NewMoon,100%", pct100, true},
+		{"30 12 30 12 *", vanatime.Date(1313, 12, 30, 12, 30, 0, 0), true},
+		{"@monthly", vanatime.Date(1313, 5, 1, 0, 0, 0, 0), true},
+		{"@weekly", vanatime.Date(1313, 4, 15, 0, 0, 0, 0), true},
+		{"@yearly", vanatime.Date(1314, 1, 1, 0, 0, 0, 0), true},
+		// 1/1 is always Firesday, as a year is 45 weeks
+		{"0 0 1 1 Earthsday", vanatime.Time{}, false},
+	}
+
+	for i, pattern := range patterns {
+		s, err := schedule.Parse(pattern.Expr)
+		if err != nil {
+			t.Errorf("[%d]: %v", i, err)
+			continue
+		}
+		got, ok := s.Next(base)
+		if !got.Equal(pattern.Want) || ok != pattern.OK {
+			t.Errorf(`[%d]: %s: want "%v", %v, but "%v", %v`, i, pattern.Expr, pattern.Want, pattern.OK, got, ok)
+		}
+		if ok && !s.Match(got) {
+			t.Errorf(`[%d]: %s: "%v" does not match`, i, pattern.Expr, got)
+		}
+	}
+}
+
+func TestNextN(t *testing.T) {
+	s := schedule.MustParse("0 */8 * * Dark")
+	got := s.NextN(base, 4)
+	want := []vanatime.Time{
+		vanatime.Date(1313, 4, 14, 0, 0, 0, 0),
+		vanatime.Date(1313, 4, 14, 8, 0, 0, 0),
+		vanatime.Date(1313, 4, 14, 16, 0, 0, 0),
+		vanatime.Date(1313, 4, 22, 0, 0, 0, 0),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d times, but %d", len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want[i], got[i])
+		}
+	}
+
+	if got := schedule.MustParse("0 0 1 1 Earthsday").NextN(base, 3); len(got) != 0 {
+		t.Errorf("want no times, but %v", got)
+	}
+	if got := s.NextN(base, -1); got != nil {
+		t.Errorf("want no times, but %v", got)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	patterns := []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 31 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * Funday",
+		"* * * * * 12",
+		"* * * * * HalfMoon",
+		"* * * * * 101%",
+		"5-3 * * * *",
+		"*/0 * * * *",
+		"1,,2 * * * *",
+		"@never",
+	}
+
+	for i, expr := range patterns {
+		if _, err := schedule.Parse(expr); err == nil {
+			t.Errorf("[%d]: %q: want error, but nil", i, expr)
+		}
+	}
+}
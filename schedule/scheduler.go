@@ -0,0 +1,213 @@
+package schedule
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// A MissedPolicy specifies what a Scheduler does when it finds that one or
+// more firings of an entry have been missed, for example because the
+// machine was suspended. A firing is missed if it is run after the
+// Vana'diel minute in which it was scheduled.
+type MissedPolicy int
+
+const (
+	// RunLatest runs the job once for the latest of the due firings.
+	RunLatest MissedPolicy = iota
+
+	// RunAll runs the job for every due firing, in order.
+	RunAll
+
+	// SkipMissed does not run the job for missed firings.
+	SkipMissed
+)
+
+// filter returns the firings to run out of the due firings at now.
+func (p MissedPolicy) filter(due []vanatime.Time, now vanatime.Time) []vanatime.Time {
+	switch p {
+	case RunAll:
+		return due
+	case SkipMissed:
+		var run []vanatime.Time
+		for _, t := range due {
+			if now.Before(t.Add(vanatime.Minute)) {
+				run = append(run, t)
+			}
+		}
+		return run
+	default:
+		if len(due) == 0 {
+			return nil
+		}
+		return due[len(due)-1:]
+	}
+}
+
+// An EntryID identifies an entry of a Scheduler.
+type EntryID int
+
+// A Firing is a scheduled run of an entry.
+type Firing struct {
+	ID   EntryID
+	Time vanatime.Time
+}
+
+// A Scheduler runs jobs on their schedules.
+//
+// Jobs are run by the timers of the clock of the scheduler, each entry on
+// its own timer, and receive the time at which they were scheduled.
+// Jobs of the same entry are never run concurrently.
+type Scheduler struct {
+	clock   vanatime.Clock
+	mu      sync.Mutex
+	lastID  EntryID
+	entries map[EntryID]*entry
+}
+
+type entry struct {
+	id       EntryID
+	schedule *Schedule
+	policy   MissedPolicy
+	f        func(vanatime.Time)
+	next     vanatime.Time
+	hasNext  bool // false if the schedule never matches again
+	timer    *vanatime.Timer
+	removed  bool
+}
+
+// NewScheduler returns a new Scheduler using the system clock.
+func NewScheduler() *Scheduler {
+	return NewSchedulerClock(vanatime.SystemClock)
+}
+
+// NewSchedulerClock is like NewScheduler but uses the given clock.
+func NewSchedulerClock(clock vanatime.Clock) *Scheduler {
+	return &Scheduler{
+		clock:   clock,
+		entries: make(map[EntryID]*entry),
+	}
+}
+
+// Add parses the schedule expression and registers f to run on it, with
+// the RunLatest policy.
+func (s *Scheduler) Add(expr string, f func(vanatime.Time)) (EntryID, error) {
+	sched, err := Parse(expr)
+	if err != nil {
+		return 0, err
+	}
+	return s.AddSchedule(sched, RunLatest, f), nil
+}
+
+// AddSchedule registers f to run on the schedule with the given policy for
+// missed firings.
+func (s *Scheduler) AddSchedule(sched *Schedule, policy MissedPolicy, f func(vanatime.Time)) EntryID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	e := &entry{
+		id:       s.lastID,
+		schedule: sched,
+		policy:   policy,
+		f:        f,
+	}
+	e.next, e.hasNext = sched.Next(s.clock.Now())
+	s.entries[e.id] = e
+	s.arm(e)
+	return e.id
+}
+
+// Remove stops the entry and removes it from the scheduler. A job which is
+// already running is not interrupted.
+func (s *Scheduler) Remove(id EntryID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[id]; ok {
+		s.remove(e)
+	}
+}
+
+// Stop stops and removes all the entries.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		s.remove(e)
+	}
+}
+
+func (s *Scheduler) remove(e *entry) {
+	e.removed = true
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	delete(s.entries, e.id)
+}
+
+// Upcoming returns the next n firings of all the entries, in order of time.
+func (s *Scheduler) Upcoming(n int) []Firing {
+	s.mu.Lock()
+	var firings []Firing
+	for _, e := range s.entries {
+		t, ok := e.next, e.hasNext
+		for i := 0; i < n && ok; i++ {
+			firings = append(firings, Firing{ID: e.id, Time: t})
+			t, ok = e.schedule.Next(t)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(firings, func(i, j int) bool {
+		if firings[i].Time.Equal(firings[j].Time) {
+			return firings[i].ID < firings[j].ID
+		}
+		return firings[i].Time.Before(firings[j].Time)
+	})
+	if len(firings) > n {
+		firings = firings[:n]
+	}
+	return firings
+}
+
+// arm starts the timer for the next firing of e. s.mu must be held.
+func (s *Scheduler) arm(e *entry) {
+	if !e.hasNext {
+		return
+	}
+	e.timer = s.clock.AfterFuncAt(e.next, func() {
+		s.fire(e)
+	})
+}
+
+func (s *Scheduler) fire(e *entry) {
+	s.mu.Lock()
+	if e.removed {
+		s.mu.Unlock()
+		return
+	}
+
+	// The timer may fire a little early because of the conversion to
+	// Earth time, or late, and then some firings may have been missed.
+	now := s.clock.Now()
+	var due []vanatime.Time
+	for e.hasNext && !e.next.After(now) {
+		due = append(due, e.next)
+		e.next, e.hasNext = e.schedule.Next(e.next)
+	}
+	s.mu.Unlock()
+
+	for _, t := range e.policy.filter(due, now) {
+		e.f(t)
+	}
+
+	// arm the timer after running the jobs, so that they never overlap
+	s.mu.Lock()
+	if !e.removed {
+		s.arm(e)
+	}
+	s.mu.Unlock()
+}
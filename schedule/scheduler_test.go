@@ -0,0 +1,139 @@
+package schedule_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/schedule"
+)
+
+func TestScheduler(t *testing.T) {
+	c := vanatime.NewManualClock(base)
+	s := schedule.NewSchedulerClock(c)
+	defer s.Stop()
+
+	var daily, lightsday []vanatime.Time
+	dailyID, err := s.Add("0 20 * * *", func(t vanatime.Time) {
+		daily = append(daily, t)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add("0 6 * * Lightsday", func(t vanatime.Time) {
+		lightsday = append(lightsday, t)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add("0 6 * * Moonday", nil); err == nil {
+		t.Error("want error, but nil")
+	}
+
+	up := s.Upcoming(3)
+	wantUp := []schedule.Firing{
+		{dailyID, vanatime.Date(1313, 4, 14, 20, 0, 0, 0)},
+		{dailyID, vanatime.Date(1313, 4, 15, 20, 0, 0, 0)},
+		{dailyID, vanatime.Date(1313, 4, 16, 20, 0, 0, 0)},
+	}
+	if len(up) != len(wantUp) {
+		t.Fatalf("want %d firings, but %d", len(wantUp), len(up))
+	}
+	for i := range wantUp {
+		if up[i].ID != wantUp[i].ID || !up[i].Time.Equal(wantUp[i].Time) {
+			t.Errorf(`[%d]: want %v, but %v`, i, wantUp[i], up[i])
+		}
+	}
+
+	c.Advance(8 * vanatime.Day)
+	if len(daily) != 8 {
+		t.Errorf("want 8 runs, but %d", len(daily))
+	}
+	for i, vt := range daily {
+		if want := vanatime.Date(1313, 4, 14+i, 20, 0, 0, 0); !vt.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, vt)
+		}
+	}
+	if len(lightsday) != 1 || !lightsday[0].Equal(vanatime.Date(1313, 4, 21, 6, 0, 0, 0)) {
+		t.Errorf("unexpected runs %v", lightsday)
+	}
+
+	s.Remove(dailyID)
+	c.Advance(8 * vanatime.Day)
+	if len(daily) != 8 {
+		t.Errorf("removed entry ran %d times", len(daily)-8)
+	}
+	if len(lightsday) != 2 {
+		t.Errorf("want 2 runs, but %d", len(lightsday))
+	}
+
+	s.Stop()
+	if got := s.Upcoming(1); len(got) != 0 {
+		t.Errorf("want no firings, but %v", got)
+	}
+	c.Advance(8 * vanatime.Day)
+	if len(lightsday) != 2 {
+		t.Errorf("stopped entry ran %d times", len(lightsday)-2)
+	}
+	if n := c.Pending(); n != 0 {
+		t.Errorf("want no timers, but %d", n)
+	}
+}
+
+// lateClock is a ManualClock whose timers fire late by lag.
+type lateClock struct {
+	*vanatime.ManualClock
+	lag vanatime.Duration
+}
+
+func (c *lateClock) Now() vanatime.Time {
+	return c.ManualClock.Now().Add(c.lag)
+}
+
+func TestMissedPolicy(t *testing.T) {
+	c := &lateClock{ManualClock: vanatime.NewManualClock(base)}
+	s := schedule.NewSchedulerClock(c)
+	defer s.Stop()
+
+	sched := schedule.MustParse("0 20 * * *")
+	runs := make(map[schedule.MissedPolicy][]vanatime.Time)
+	for _, p := range []schedule.MissedPolicy{schedule.RunLatest, schedule.RunAll, schedule.SkipMissed} {
+		p := p
+		s.AddSchedule(sched, p, func(t vanatime.Time) {
+			runs[p] = append(runs[p], t)
+		})
+	}
+
+	// the firing at 04-14 20:00 runs at 04-16 20:30
+	c.lag = 2*vanatime.Day + 30*vanatime.Minute
+	c.Advance(vanatime.Day)
+
+	want := map[schedule.MissedPolicy][]vanatime.Time{
+		schedule.RunLatest: {vanatime.Date(1313, 4, 16, 20, 0, 0, 0)},
+		schedule.RunAll: {
+			vanatime.Date(1313, 4, 14, 20, 0, 0, 0),
+			vanatime.Date(1313, 4, 15, 20, 0, 0, 0),
+			vanatime.Date(1313, 4, 16, 20, 0, 0, 0),
+		},
+		schedule.SkipMissed: nil,
+	}
+	for p, times := range want {
+		if len(runs[p]) != len(times) {
+			t.Errorf("policy %d: want %v, but %v", p, times, runs[p])
+			continue
+		}
+		for i := range times {
+			if !runs[p][i].Equal(times[i]) {
+				t.Errorf(`policy %d: [%d]: want "%v", but "%v"`, p, i, times[i], runs[p][i])
+			}
+		}
+	}
+
+	// on time firings run with every policy
+	c.lag = 0
+	c.Advance(3 * vanatime.Day)
+	for p := range want {
+		last := runs[p][len(runs[p])-1]
+		if want := vanatime.Date(1313, 4, 17, 20, 0, 0, 0); !last.Equal(want) {
+			t.Errorf(`policy %d: want "%v", but "%v"`, p, want, last)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package server provides an HTTP API of Vana'diel time, so that programs
+// in other languages do not have to reimplement the conversions.
+//
+// The handler serves JSON on the following endpoints, with the Vana'diel
+// times in the DateTimeMicro format of vanatime.Time.MarshalJSON:
+//
+//     GET /now                             the current time
+//     GET /convert?earth=2018-11-05T21:58:25+09:00
+//                                          an Earth time (RFC 3339) in Vana'diel time
+//     GET /convert?vana=1313-04-13+21:20:27
+//                                          a Vana'diel time in Earth time
+//     GET /moon[?vana=...]                 the moon, now or at a Vana'diel time
+//     GET /upcoming?event=fullmoon&n=5[&vana=...]
+//                                          the next events, now or after a Vana'diel time
+//     GET /stream                          Server-Sent Events at every Vana'diel minute
+//
+// The events of /upcoming are day, week, phase (any change of the moon
+// phase), conquest (the Conquest tally), the days of the week (firesday,
+// earthsday, ..., darksday) and the moon phases (newmoon, waxingcrescent,
+// firstquarter, waxinggibbous, fullmoon, waninggibbous, lastquarter and
+// waningcrescent).
+//
+// The names of the days and the moon phases are in English, or in the
+// locale given by the lang parameter, such as lang=ja. Errors are returned
+// as {"error": "message"} with a 4xx status.
+//
+// The handler can be mounted in an existing server:
+//
+//     http.Handle("/vanatime/", http.StripPrefix("/vanatime", server.NewHandler()))
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/conquest"
+)
+
+// MaxUpcoming is the maximum number of events returned by /upcoming.
+const MaxUpcoming = 100
+
+// A Handler serves the API.
+type Handler struct {
+	clock  vanatime.Clock
+	mux    *http.ServeMux
+	stream *Broadcaster
+}
+
+// NewHandler returns a new Handler using the system clock.
+func NewHandler() *Handler {
+	return NewHandlerClock(vanatime.SystemClock)
+}
+
+// NewHandlerClock is like NewHandler but uses the given clock.
+func NewHandlerClock(clock vanatime.Clock) *Handler {
+	h := &Handler{
+		clock:  clock,
+		mux:    http.NewServeMux(),
+		stream: NewBroadcasterClock(clock, vanatime.Minute),
+	}
+	h.mux.HandleFunc("/now", h.now)
+	h.mux.HandleFunc("/convert", h.convert)
+	h.mux.HandleFunc("/moon", h.moon)
+	h.mux.HandleFunc("/upcoming", h.upcoming)
+	h.mux.Handle("/stream", NewStreamHandler(h.stream))
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if _, pattern := h.mux.Handler(r); pattern == "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// TimeInfo is the response of /now and /convert.
+type TimeInfo struct {
+	Vana    vanatime.Time `json:"vana"`
+	Earth   time.Time     `json:"earth"`
+	Weekday string        `json:"weekday"`
+	Element string        `json:"element"`
+	Moon    MoonInfo      `json:"moon"`
+}
+
+// MoonInfo is the response of /moon.
+type MoonInfo struct {
+	Phase        string            `json:"phase"`
+	PhaseIndex   int               `json:"phase_index"`
+	Percent      int               `json:"percent"`
+	PercentFloat float64           `json:"percent_float"`
+	Age          int               `json:"age"`
+	Cycle        int               `json:"cycle"`
+	Waxing       bool              `json:"waxing"`
+	PhaseStart   vanatime.Time     `json:"phase_start"`
+	PhaseEnd     vanatime.Time     `json:"phase_end"`
+	PhaseLeft    vanatime.Duration `json:"phase_left"`
+}
+
+// An Event is an element of the response of /upcoming.
+type Event struct {
+	Event string        `json:"event"`
+	Name  string        `json:"name"`
+	Vana  vanatime.Time `json:"vana"`
+	Earth time.Time     `json:"earth"`
+}
+
+func newTimeInfo(t vanatime.Time, locale string) TimeInfo {
+	return TimeInfo{
+		Vana:    t,
+		Earth:   t.Earth(),
+		Weekday: t.Weekday().StringLocale(locale),
+		Element: t.Weekday().Element().StringLocale(locale),
+		Moon:    newMoonInfo(t, locale),
+	}
+}
+
+func newMoonInfo(t vanatime.Time, locale string) MoonInfo {
+	m := t.Moon()
+	end := vanatime.PhaseEnd(t)
+	return MoonInfo{
+		Phase:        m.Phase().StringLocale(locale),
+		PhaseIndex:   int(m.Phase()),
+		Percent:      m.Percent(),
+		PercentFloat: m.PercentFloat(),
+		Age:          m.Age(),
+		Cycle:        m.Cycle(),
+		Waxing:       m.Waxing(),
+		PhaseStart:   vanatime.PhaseStart(t),
+		PhaseEnd:     end,
+		PhaseLeft:    end.Sub(t),
+	}
+}
+
+func (h *Handler) now(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, newTimeInfo(h.clock.Now(), locale(r)))
+}
+
+func (h *Handler) convert(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	earth, vana := q.Get("earth"), q.Get("vana")
+	if (earth == "") == (vana == "") {
+		writeError(w, http.StatusBadRequest, "either earth or vana is required")
+		return
+	}
+
+	var t vanatime.Time
+	if earth != "" {
+		et, err := time.Parse(time.RFC3339Nano, earth)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid Earth time %q", earth))
+			return
+		}
+		t = vanatime.FromEarth(et)
+	} else {
+		var err error
+		if t, err = parseVana(vana); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	writeJSON(w, newTimeInfo(t, locale(r)))
+}
+
+func (h *Handler) moon(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.timeParam(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, newMoonInfo(t, locale(r)))
+}
+
+func (h *Handler) upcoming(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.timeParam(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	name := strings.ToLower(q.Get("event"))
+	e, ok := events[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown event %q", q.Get("event")))
+		return
+	}
+	n := 1
+	if s := q.Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n < 1 || n > MaxUpcoming {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("n must be 1 to %d", MaxUpcoming))
+			return
+		}
+	}
+
+	lang := locale(r)
+	list := make([]Event, n)
+	for i := range list {
+		t = e.next(t)
+		list[i] = Event{
+			Event: name,
+			Name:  e.name(t, lang),
+			Vana:  t,
+			Earth: t.Earth(),
+		}
+	}
+	writeJSON(w, list)
+}
+
+// An event is an event of /upcoming.
+type event struct {
+	next func(t vanatime.Time) vanatime.Time // the first event after t
+	name func(t vanatime.Time, locale string) string
+}
+
+var events = map[string]event{
+	"day":      {truncateNext(vanatime.Day), weekdayName},
+	"week":     {truncateNext(vanatime.Week), weekdayName},
+	"phase":    {vanatime.PhaseEnd, phaseName},
+	"conquest": {conquest.NextTally, func(vanatime.Time, string) string { return "Conquest tally" }},
+}
+
+func init() {
+	// firesday, ..., darksday
+	for w := vanatime.Firesday; w <= vanatime.Darksday; w++ {
+		e := w.Element()
+		next := func(t vanatime.Time) vanatime.Time {
+			return vanatime.NextDayOfElement(t, e)
+		}
+		events[strings.ToLower(w.String())] = event{next, weekdayName}
+	}
+
+	// newmoon, waxingcrescent, ..., fullmoon, ..., waningcrescent
+	for p := vanatime.NewMoon; p <= vanatime.WaningCrescent2; p++ {
+		key := strings.ToLower(strings.Replace(p.String(), " ", "", -1))
+		if _, ok := events[key]; ok {
+			continue // the second phase of the same name
+		}
+		p := p
+		next := func(t vanatime.Time) vanatime.Time {
+			return vanatime.NextPhase(t, p)
+		}
+		events[key] = event{next, phaseName}
+	}
+}
+
+func truncateNext(d vanatime.Duration) func(vanatime.Time) vanatime.Time {
+	return func(t vanatime.Time) vanatime.Time {
+		return t.Truncate(d).Add(d)
+	}
+}
+
+func weekdayName(t vanatime.Time, locale string) string {
+	return t.Weekday().StringLocale(locale)
+}
+
+func phaseName(t vanatime.Time, locale string) string {
+	return t.Moon().Phase().StringLocale(locale)
+}
+
+// timeParam returns the time of the vana parameter, or the current time.
+// It writes an error and returns false if the parameter is invalid.
+func (h *Handler) timeParam(w http.ResponseWriter, r *http.Request) (vanatime.Time, bool) {
+	s := r.URL.Query().Get("vana")
+	if s == "" {
+		return h.clock.Now(), true
+	}
+	t, err := parseVana(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return vanatime.Time{}, false
+	}
+	return t, true
+}
+
+// parseVana parses a Vana'diel date and time, or date.
+func parseVana(s string) (vanatime.Time, error) {
+	for _, layout := range []string{vanatime.DateTimeMicro, vanatime.DateTime, vanatime.DateOnly} {
+		if t, err := vanatime.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return vanatime.Time{}, fmt.Errorf("invalid Vana'diel time %q", s)
+}
+
+func locale(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return "en"
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("server: writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": msg}); err != nil {
+		log.Printf("server: writing response: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/conquest"
+	"github.com/pasela/go-vanatime/server"
+)
+
+var testNow = vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+
+func get(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+	h := server.NewHandlerClock(vanatime.NewManualClock(testNow))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if want, got := "application/json; charset=utf-8", rec.Header().Get("Content-Type"); got != want {
+		t.Errorf(`GET %s: want "%v", but "%v"`, url, want, got)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: %v: %s", url, err, rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestNow(t *testing.T) {
+	var info server.TimeInfo
+	if code := get(t, "/now", &info); code != http.StatusOK {
+		t.Fatalf("want %d, but %d", http.StatusOK, code)
+	}
+	if !info.Vana.Equal(testNow) {
+		t.Errorf(`want "%v", but "%v"`, testNow, info.Vana)
+	}
+	if !info.Earth.Equal(testNow.Earth()) {
+		t.Errorf(`want "%v", but "%v"`, testNow.Earth(), info.Earth)
+	}
+
+	patterns := []struct {
+		Lang    string
+		Weekday string
+		Element string
+	}{
+		{"", "Lightsday", "Light"},
+		{"ja", "光曜日", "光"},
+	}
+
+	for i, pattern := range patterns {
+		get(t, "/now?lang="+pattern.Lang, &info)
+		if info.Weekday != pattern.Weekday {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Weekday, info.Weekday)
+		}
+		if info.Element != pattern.Element {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Element, info.Element)
+		}
+		if info.Moon.Percent != 33 {
+			t.Errorf(`[%d]: want 33, but %d`, i, info.Moon.Percent)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	patterns := []struct {
+		URL  string
+		Want vanatime.Time
+	}{
+		{"/convert?earth=2018-11-05T21:58:25%2B09:00", vanatime.Date(1313, 4, 13, 21, 20, 25, 0)},
+		{"/convert?vana=1313-04-13+21:20:25", vanatime.FromEarth(time.Date(2018, 11, 5, 12, 58, 25, 0, time.UTC))},
+	}
+
+	for i, pattern := range patterns {
+		var info server.TimeInfo
+		if code := get(t, pattern.URL, &info); code != http.StatusOK {
+			t.Fatalf("[%d]: want %d, but %d", i, http.StatusOK, code)
+		}
+		if !info.Vana.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, info.Vana)
+		}
+		if !info.Earth.Equal(pattern.Want.Earth()) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want.Earth(), info.Earth)
+		}
+	}
+}
+
+func TestMoon(t *testing.T) {
+	var info server.MoonInfo
+	if code := get(t, "/moon?vana=1313-04-15", &info); code != http.StatusOK {
+		t.Fatalf("want %d, but %d", http.StatusOK, code)
+	}
+	at := vanatime.Date(1313, 4, 15, 0, 0, 0, 0)
+	m := at.Moon()
+	want := server.MoonInfo{
+		Phase:   m.Phase().String(),
+		Percent: m.Percent(),
+		Age:     m.Age(),
+		Waxing:  m.Waxing(),
+	}
+	got := server.MoonInfo{
+		Phase:   info.Phase,
+		Percent: info.Percent,
+		Age:     info.Age,
+		Waxing:  info.Waxing,
+	}
+	if got != want {
+		t.Errorf(`want "%+v", but "%+v"`, want, got)
+	}
+	end := vanatime.PhaseEnd(at)
+	if !info.PhaseEnd.Equal(end) {
+		t.Errorf(`want "%v", but "%v"`, end, info.PhaseEnd)
+	}
+	if info.PhaseLeft != end.Sub(at) {
+		t.Errorf(`want "%v", but "%v"`, end.Sub(at), info.PhaseLeft)
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	patterns := []struct {
+		URL  string
+		Want []vanatime.Time
+	}{
+		{"/upcoming?event=day&n=2", []vanatime.Time{
+			vanatime.Date(1313, 4, 14, 0, 0, 0, 0),
+			vanatime.Date(1313, 4, 15, 0, 0, 0, 0),
+		}},
+		{"/upcoming?event=Firesday&n=2", []vanatime.Time{
+			vanatime.Date(1313, 4, 15, 0, 0, 0, 0),
+			vanatime.Date(1313, 4, 23, 0, 0, 0, 0),
+		}},
+		{"/upcoming?event=conquest", conquestTallies(1)},
+	}
+
+	for i, pattern := range patterns {
+		var events []server.Event
+		if code := get(t, pattern.URL, &events); code != http.StatusOK {
+			t.Fatalf("[%d]: want %d, but %d", i, http.StatusOK, code)
+		}
+		if len(events) != len(pattern.Want) {
+			t.Fatalf("[%d]: want %d events, but %d", i, len(pattern.Want), len(events))
+		}
+		for j, e := range events {
+			if !e.Vana.Equal(pattern.Want[j]) {
+				t.Errorf(`[%d][%d]: want "%v", but "%v"`, i, j, pattern.Want[j], e.Vana)
+			}
+			if !e.Earth.Equal(pattern.Want[j].Earth()) {
+				t.Errorf(`[%d][%d]: want "%v", but "%v"`, i, j, pattern.Want[j].Earth(), e.Earth)
+			}
+		}
+	}
+}
+
+func conquestTallies(n int) []vanatime.Time {
+	var times []vanatime.Time
+	for _, tally := range conquest.NextTallies(testNow, n) {
+		times = append(times, tally.Time)
+	}
+	return times
+}
+
+func TestUpcomingFullMoon(t *testing.T) {
+	var events []server.Event
+	if code := get(t, "/upcoming?event=fullmoon&n=5", &events); code != http.StatusOK {
+		t.Fatalf("want %d, but %d", http.StatusOK, code)
+	}
+	if len(events) != 5 {
+		t.Fatalf("want 5 events, but %d", len(events))
+	}
+	want := vanatime.NextPhase(testNow, vanatime.FullMoon)
+	for i, e := range events {
+		if e.Event != "fullmoon" || e.Name != "Full Moon" {
+			t.Errorf(`[%d]: want "fullmoon", "Full Moon", but "%v", "%v"`, i, e.Event, e.Name)
+		}
+		if !e.Vana.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, e.Vana)
+		}
+		want = want.Add(vanatime.Duration(vanatime.MoonCycleDays) * vanatime.Day)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	patterns := []struct {
+		Method string
+		URL    string
+		Code   int
+	}{
+		{http.MethodGet, "/convert", http.StatusBadRequest},
+		{http.MethodGet, "/convert?earth=yesterday", http.StatusBadRequest},
+		{http.MethodGet, "/convert?earth=2018-11-05T21:58:25Z&vana=1313-04-13", http.StatusBadRequest},
+		{http.MethodGet, "/moon?vana=1313-13-01", http.StatusBadRequest},
+		{http.MethodGet, "/upcoming?event=bluemoon", http.StatusBadRequest},
+		{http.MethodGet, "/upcoming?event=day&n=0", http.StatusBadRequest},
+		{http.MethodGet, "/upcoming?event=day&n=1000", http.StatusBadRequest},
+		{http.MethodGet, "/later", http.StatusNotFound},
+		{http.MethodPost, "/now", http.StatusMethodNotAllowed},
+	}
+
+	h := server.NewHandler()
+	for i, pattern := range patterns {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(pattern.Method, pattern.URL, nil))
+		var body struct{ Error string }
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error == "" {
+			t.Errorf(`[%d]: want an error, but "%v"`, i, rec.Body.String())
+		}
+		if rec.Code != pattern.Code {
+			t.Errorf(`[%d]: want %d, but %d`, i, pattern.Code, rec.Code)
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	ts := httptest.NewServer(http.StripPrefix("/vanatime", server.NewHandler()))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/vanatime/now")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var info server.TimeInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if d := vanatime.Now().Sub(info.Vana); d < 0 || d > vanatime.Hour {
+		t.Errorf(`want a time within an hour, but "%v" (%v ago)`, info.Vana, d)
+	}
+}
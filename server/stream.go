@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// A Broadcaster sends the ticks of a single aligned Ticker to any number of
+// subscribers, so that they do not need a Ticker, and an Earth ticker, each.
+//
+// The Ticker is started by the first subscriber and stopped when the last
+// one unsubscribes. Like a Ticker, a Broadcaster drops the ticks for a
+// subscriber which has not received the previous tick.
+type Broadcaster struct {
+	clock vanatime.Clock
+	unit  vanatime.Duration
+
+	mu     sync.Mutex
+	subs   map[chan vanatime.Time]struct{}
+	ticker *vanatime.Ticker
+	done   chan struct{}
+}
+
+// NewBroadcaster returns a new Broadcaster of the ticks at every multiple
+// of unit since the zero time, such as every Vana'diel minute for
+// vanatime.Minute, using the system clock. The unit must be greater than
+// zero.
+func NewBroadcaster(unit vanatime.Duration) *Broadcaster {
+	return NewBroadcasterClock(vanatime.SystemClock, unit)
+}
+
+// NewBroadcasterClock is like NewBroadcaster but uses the given clock.
+func NewBroadcasterClock(clock vanatime.Clock, unit vanatime.Duration) *Broadcaster {
+	return &Broadcaster{
+		clock: clock,
+		unit:  unit,
+		subs:  make(map[chan vanatime.Time]struct{}),
+	}
+}
+
+// Subscribe returns a channel which receives the ticks, and a function to
+// unsubscribe, which must be called once the ticks are no longer needed.
+// The channel is not closed.
+func (b *Broadcaster) Subscribe() (ticks <-chan vanatime.Time, unsubscribe func()) {
+	ch := make(chan vanatime.Time, 1)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	if b.ticker == nil {
+		b.ticker = b.clock.NewAlignedTicker(b.unit)
+		b.done = make(chan struct{})
+		go b.run(b.ticker, b.done)
+	}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.unsubscribe(ch)
+		})
+	}
+}
+
+func (b *Broadcaster) unsubscribe(ch chan vanatime.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subs, ch)
+	if len(b.subs) == 0 && b.ticker != nil {
+		b.ticker.Stop()
+		close(b.done)
+		b.ticker, b.done = nil, nil
+	}
+}
+
+func (b *Broadcaster) run(ticker *vanatime.Ticker, done <-chan struct{}) {
+	for {
+		select {
+		case t := <-ticker.C:
+			b.mu.Lock()
+			for ch := range b.subs {
+				select {
+				case ch <- t:
+				default:
+				}
+			}
+			b.mu.Unlock()
+		case <-done:
+			return
+		}
+	}
+}
+
+// A StreamHandler streams the ticks of a Broadcaster as Server-Sent Events,
+// which browsers receive with the EventSource API. Each event is a "tick"
+// event whose data is the TimeInfo of the tick, as returned by /now:
+//
+//     event: tick
+//     data: {"vana":"1313-04-13 21:21:00.000000","earth":...,"weekday":"Lightsday",...}
+//
+// The first event, sent on connection, is the current time. The names are
+// localized by the lang parameter, as in Handler.
+//
+// WebSocket is not supported, as it would need a dependency on a WebSocket
+// package; SSE suffices for a one-way feed.
+type StreamHandler struct {
+	b *Broadcaster
+}
+
+// NewStreamHandler returns a new StreamHandler of the ticks of b.
+func NewStreamHandler(b *Broadcaster) *StreamHandler {
+	return &StreamHandler{b: b}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	ticks, unsubscribe := h.b.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	lang := locale(r)
+	send := func(t vanatime.Time) error {
+		data, err := json.Marshal(newTimeInfo(t, lang))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("event: tick\ndata: " + string(data) + "\n\n")); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	if send(h.b.clock.Now()) != nil {
+		return
+	}
+	for {
+		select {
+		case t := <-ticks:
+			if send(t) != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/server"
+)
+
+func TestBroadcaster(t *testing.T) {
+	clock := vanatime.NewManualClock(testNow)
+	b := server.NewBroadcasterClock(clock, vanatime.Minute)
+
+	ch1, unsubscribe1 := b.Subscribe()
+	ch2, unsubscribe2 := b.Subscribe()
+
+	want := vanatime.Date(1313, 4, 13, 21, 21, 0, 0)
+	clock.Advance(vanatime.Minute)
+	for i, ch := range []<-chan vanatime.Time{ch1, ch2} {
+		select {
+		case got := <-ch:
+			if !got.Equal(want) {
+				t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("[%d]: want a tick, but none", i)
+		}
+	}
+
+	unsubscribe1()
+	unsubscribe1() // no-op
+	clock.Advance(vanatime.Minute)
+	select {
+	case <-ch2:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after another subscriber left")
+	}
+	select {
+	case got := <-ch1:
+		t.Errorf(`want no tick, but "%v"`, got)
+	default:
+	}
+
+	// resubscribing after all have left restarts the ticker
+	unsubscribe2()
+	clock.Advance(vanatime.Minute)
+	ch3, unsubscribe3 := b.Subscribe()
+	defer unsubscribe3()
+	want = vanatime.Date(1313, 4, 13, 21, 24, 0, 0)
+	clock.Advance(vanatime.Minute)
+	select {
+	case got := <-ch3:
+		if !got.Equal(want) {
+			t.Errorf(`want "%v", but "%v"`, want, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no tick after resubscribing")
+	}
+}
+
+func TestStream(t *testing.T) {
+	clock := vanatime.NewManualClock(testNow)
+	ts := httptest.NewServer(server.NewHandlerClock(clock))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/stream?lang=ja")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if want, got := "text/event-stream", resp.Header.Get("Content-Type"); got != want {
+		t.Errorf(`want "%v", but "%v"`, want, got)
+	}
+
+	events := make(chan server.TimeInfo)
+	go func() {
+		defer close(events)
+		sc := bufio.NewScanner(resp.Body)
+		event := ""
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: ") && event == "tick":
+				var info server.TimeInfo
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &info); err != nil {
+					t.Error(err)
+					return
+				}
+				events <- info
+			}
+		}
+	}()
+
+	first := <-events
+	if !first.Vana.Equal(testNow) {
+		t.Errorf(`want "%v", but "%v"`, testNow, first.Vana)
+	}
+	if first.Weekday != "光曜日" {
+		t.Errorf(`want "%v", but "%v"`, "光曜日", first.Weekday)
+	}
+
+	// the subscription may be made after an advance, so advance until a tick
+	want := vanatime.Date(1313, 4, 13, 21, 21, 0, 0)
+	for {
+		select {
+		case info, ok := <-events:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			if info.Vana.Before(want) || !info.Vana.Equal(info.Vana.Truncate(vanatime.Minute)) {
+				t.Errorf(`want a minute from "%v", but "%v"`, want, info.Vana)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+			clock.Advance(vanatime.Minute)
+		}
+	}
+}
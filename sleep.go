@@ -0,0 +1,70 @@
+package vanatime
+
+import (
+	"context"
+	"time"
+)
+
+// maxSleep is the longest Earth duration SleepUntil waits before checking
+// the current time again, so that it notices when the system clock jumps.
+const maxSleep = time.Minute
+
+// SleepContext pauses the current goroutine for at least the duration d, or
+// until ctx is done. It returns ctx.Err() if ctx is done before d elapses,
+// and nil otherwise.
+// A negative or zero duration causes SleepContext to return nil immediately.
+func SleepContext(ctx context.Context, d Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	return SleepUntil(ctx, Now().Add(d))
+}
+
+// SleepUntil pauses the current goroutine until the Vana'diel time t, or
+// until ctx is done. It returns ctx.Err() if ctx is done before t, and nil
+// otherwise. If t is not after the current time, SleepUntil returns nil
+// immediately.
+//
+// The wait is computed from the exact Earth time of t and rechecked against
+// the current time on every wake up, at least once an Earth minute, so
+// SleepUntil does not drift on long waits and follows changes of the system
+// clock.
+func SleepUntil(ctx context.Context, t Time) error {
+	var timer *time.Timer
+	for {
+		d := time.Until(t.Earth())
+		if d <= 0 {
+			return nil
+		}
+		if d > maxSleep {
+			d = maxSleep
+		}
+		if timer == nil {
+			timer = time.NewTimer(d)
+			defer timer.Stop()
+		} else {
+			timer.Reset(d)
+		}
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitUntil waits until the Vana'diel time t as SleepUntil does, and then
+// sends the current time on the returned channel. If ctx is done before t,
+// the channel is closed without sending a value.
+func WaitUntil(ctx context.Context, t Time) <-chan Time {
+	c := make(chan Time, 1)
+	go func() {
+		if err := SleepUntil(ctx, t); err != nil {
+			close(c)
+			return
+		}
+		c <- Now()
+	}()
+	return c
+}
@@ -0,0 +1,78 @@
+package vanatime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func TestSleepContext(t *testing.T) {
+	// a Vana'diel second is 40ms on the Earth
+	d := 2 * vanatime.Second
+	start := vanatime.Now()
+	if err := vanatime.SleepContext(context.Background(), d); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := vanatime.Since(start); elapsed < d {
+		t.Errorf("slept %v, want at least %v", elapsed, d)
+	}
+
+	if err := vanatime.SleepContext(context.Background(), -1); err != nil {
+		t.Errorf("want nil, but %v", err)
+	}
+}
+
+func TestSleepContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := vanatime.Now()
+	if err := vanatime.SleepContext(ctx, vanatime.Day); err != context.Canceled {
+		t.Errorf("want %v, but %v", context.Canceled, err)
+	}
+	if elapsed := vanatime.Since(start); elapsed >= vanatime.Minute {
+		t.Errorf("cancelled sleep took %v", elapsed)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+	if err := vanatime.SleepContext(ctx, vanatime.Day); err != context.DeadlineExceeded {
+		t.Errorf("want %v, but %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestSleepUntil(t *testing.T) {
+	target := vanatime.Now().Add(2 * vanatime.Second)
+	if err := vanatime.SleepUntil(context.Background(), target); err != nil {
+		t.Fatal(err)
+	}
+	if now := vanatime.Now(); now.Before(target) {
+		t.Errorf(`woke up at "%v", before "%v"`, now, target)
+	}
+
+	// the past does not block, even if ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := vanatime.SleepUntil(ctx, target); err != nil {
+		t.Errorf("want nil, but %v", err)
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	target := vanatime.Now().Add(2 * vanatime.Second)
+	v, ok := <-vanatime.WaitUntil(context.Background(), target)
+	if !ok {
+		t.Fatal("channel closed")
+	}
+	if v.Before(target) {
+		t.Errorf(`received "%v", before "%v"`, v, target)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := vanatime.WaitUntil(ctx, vanatime.Now().Add(vanatime.Day))
+	cancel()
+	if v, ok := <-c; ok {
+		t.Errorf(`want closed channel, but received "%v"`, v)
+	}
+}
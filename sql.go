@@ -0,0 +1,94 @@
+package vanatime
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Scan implements the sql.Scanner interface.
+// It accepts an integer count of microseconds since C.E. 0001-01-01 00:00:00
+// (as returned by Int64), text in the DateTimeMicro format, or an Earth
+// time.Time.
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*t = FromInt64(v)
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	case time.Time:
+		*t = FromEarth(v)
+		return nil
+	case nil:
+		return errors.New("vanatime: cannot scan NULL into Time")
+	}
+	return fmt.Errorf("vanatime: cannot scan %T into Time", src)
+}
+
+// Value implements the driver.Valuer interface.
+// The time is stored as an integer count of microseconds since
+// C.E. 0001-01-01 00:00:00, as returned by Int64.
+func (t Time) Value() (driver.Value, error) {
+	return t.time, nil
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts an integer count of microseconds, or text accepted by
+// ParseDuration.
+func (d *Duration) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case int64:
+		*d = Duration(v)
+		return nil
+	case string:
+		*d, err = ParseDuration(v)
+		return err
+	case []byte:
+		*d, err = ParseDuration(string(v))
+		return err
+	case nil:
+		return errors.New("vanatime: cannot scan NULL into Duration")
+	}
+	return fmt.Errorf("vanatime: cannot scan %T into Duration", src)
+}
+
+// Value implements the driver.Valuer interface.
+// The duration is stored as an integer count of microseconds.
+func (d Duration) Value() (driver.Value, error) {
+	return int64(d), nil
+}
+
+// NullTime represents a Time that may be null.
+// NullTime implements the sql.Scanner interface so
+// it can be used as a scan destination, similar to sql.NullTime.
+type NullTime struct {
+	Time  Time
+	Valid bool // Valid is true if Time is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (nt *NullTime) Scan(value interface{}) error {
+	if value == nil {
+		nt.Time, nt.Valid = Time{}, false
+		return nil
+	}
+	if err := nt.Time.Scan(value); err != nil {
+		nt.Valid = false
+		return err
+	}
+	nt.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (nt NullTime) Value() (driver.Value, error) {
+	if !nt.Valid {
+		return nil, nil
+	}
+	return nt.Time.Value()
+}
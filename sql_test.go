@@ -0,0 +1,135 @@
+package vanatime_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+var (
+	_ sql.Scanner   = (*vanatime.Time)(nil)
+	_ driver.Valuer = vanatime.Time{}
+	_ sql.Scanner   = (*vanatime.Duration)(nil)
+	_ driver.Valuer = vanatime.Duration(0)
+	_ sql.Scanner   = (*vanatime.NullTime)(nil)
+	_ driver.Valuer = vanatime.NullTime{}
+)
+
+func TestTimeScan(t *testing.T) {
+	want := vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)
+	sources := []interface{}{
+		want.Int64(),
+		"1313-04-13 21:20:27.654321",
+		[]byte("1313-04-13 21:20:27.654321"),
+		want.Earth(),
+	}
+
+	for i, src := range sources {
+		var got vanatime.Time
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+	}
+}
+
+func TestTimeScanError(t *testing.T) {
+	sources := []interface{}{
+		nil,
+		1.5,
+		true,
+		"1313-04-13",
+	}
+
+	for i, src := range sources {
+		var vt vanatime.Time
+		if err := vt.Scan(src); err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+		}
+	}
+}
+
+func TestTimeValue(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)
+	got, err := vt.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != vt.Int64() {
+		t.Errorf("want %v, but %v", vt.Int64(), got)
+	}
+	if !driver.IsValue(got) {
+		t.Errorf("%T is not a driver.Value", got)
+	}
+}
+
+func TestDurationScan(t *testing.T) {
+	want := 3*vanatime.Hour + 30*vanatime.Minute
+	sources := []interface{}{
+		int64(want),
+		"3h30m",
+		[]byte("3h30m"),
+	}
+
+	for i, src := range sources {
+		var got vanatime.Duration
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("[%d]: %s", i, err)
+		}
+		if got != want {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got)
+		}
+	}
+
+	var d vanatime.Duration
+	if err := d.Scan(time.Second); err == nil {
+		t.Errorf("want error, but nil")
+	}
+}
+
+func TestDurationValue(t *testing.T) {
+	d := 3*vanatime.Hour + 30*vanatime.Minute
+	got, err := d.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != int64(d) {
+		t.Errorf("want %v, but %v", int64(d), got)
+	}
+}
+
+func TestNullTime(t *testing.T) {
+	var nt vanatime.NullTime
+	if err := nt.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if nt.Valid {
+		t.Errorf("want invalid, but valid")
+	}
+	if v, err := nt.Value(); err != nil || v != nil {
+		t.Errorf("want nil, but %v (%v)", v, err)
+	}
+
+	want := vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)
+	if err := nt.Scan(want.Int64()); err != nil {
+		t.Fatal(err)
+	}
+	if !nt.Valid || !nt.Time.Equal(want) {
+		t.Errorf(`want "%v", but "%v" (valid=%v)`, want, nt.Time, nt.Valid)
+	}
+	if v, err := nt.Value(); err != nil || v != want.Int64() {
+		t.Errorf("want %v, but %v (%v)", want.Int64(), v, err)
+	}
+
+	if err := nt.Scan(1.5); err == nil {
+		t.Errorf("want error, but nil")
+	}
+	if nt.Valid {
+		t.Errorf("want invalid after error, but valid")
+	}
+}
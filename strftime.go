@@ -9,7 +9,7 @@ import (
 )
 
 var defaultFormatPadding = map[rune]string{
-	'e': " ", 'k': " ", 'A': " ", 'n': " ", 't': " ", '%': " ",
+	'e': " ", 'k': " ", 'A': " ", 'a': " ", 'n': " ", 't': " ", '%': " ",
 }
 
 func formatPadding(r rune) string {
@@ -85,6 +85,7 @@ func formatWidth(r rune) int {
 //     Weekday:
 //       %A - The full weekday name (``Firesday'')
 //               %^A  uppercased (``FIRESDAY'')
+//       %a - The abbreviated weekday name (``Fir'')
 //       %w - Day of the week (Firesday is 0, 0..7)
 //
 //     Seconds since the Epoch:
@@ -100,7 +101,19 @@ func formatWidth(r rune) int {
 //       %X - Same as %T
 //       %R - 24-hour time (%H:%M)
 //       %T - 24-hour time (%H:%M:%S)
+//
+// The names are in English. Use StrftimeLocale for other locales.
 func (t Time) Strftime(format string) string {
+	return t.strftime(format, &english)
+}
+
+// StrftimeLocale is like Strftime but formats the names (%A, %a) by
+// specified locale. See RegisterLocale for the supported locales.
+func (t Time) StrftimeLocale(format, locale string) string {
+	return t.strftime(format, lookupLocale(locale))
+}
+
+func (t Time) strftime(format string, names *Catalog) string {
 	year, mon, day, yday := t.Date()
 	hour, min, sec := t.Clock()
 	usec := t.Microsecond()
@@ -111,7 +124,7 @@ func (t Time) Strftime(format string) string {
 		'm': int64(mon), 'd': int64(day), 'e': int64(day), 'j': int64(yday),
 		'H': int64(hour), 'k': int64(hour), 'M': int64(min), 'S': int64(sec),
 		'L': int64(usec), 'N': int64(usec),
-		'A': int64(wday), 'w': int64(wday), 's': t.time,
+		'A': int64(wday), 'w': int64(wday), 's': floorDiv(t.time, int64(Second)),
 	}
 
 	format = normalizeFormat(format)
@@ -157,7 +170,9 @@ func (t Time) Strftime(format string) string {
 			value = strconv.Itoa(v)
 
 		case 'A':
-			value = wday.String()
+			value = names.Weekdays[wday]
+		case 'a':
+			value = names.WeekdayAbbrs[wday]
 
 		case 'n':
 			value = "\n"
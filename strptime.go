@@ -0,0 +1,366 @@
+package vanatime
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A ParseError describes a problem parsing a time string.
+type ParseError struct {
+	Format    string // the format string
+	Value     string // the value being parsed
+	Directive string // the directive (or layout element) that failed, if any
+	Offset    int    // byte offset in Value where the error was found
+	Message   string
+}
+
+// Error returns the string representation of a ParseError.
+func (e *ParseError) Error() string {
+	s := "vanatime: parsing time " + strconv.Quote(e.Value) + " as " + strconv.Quote(e.Format) + ": " + e.Message
+	if e.Directive != "" {
+		s += " for " + e.Directive
+	}
+	return s + " at offset " + strconv.Itoa(e.Offset)
+}
+
+var strptimeDirective = regexp.MustCompile(`%([-_0^#]+)?(\d+)?([YCymdejHkMSLNAawsnt%])`)
+
+// directives which take numerical values
+const numericConversions = "YCymdejHkMSLNws"
+
+type strptimeState struct {
+	format string
+	value  string
+	pos    int
+}
+
+func (s *strptimeState) errorf(directive string, offset int, msg string) error {
+	return &ParseError{
+		Format:    s.format,
+		Value:     s.value,
+		Directive: directive,
+		Offset:    offset,
+		Message:   msg,
+	}
+}
+
+func (s *strptimeState) skipSpaces() int {
+	n := 0
+	for s.pos < len(s.value) && s.value[s.pos] == ' ' {
+		s.pos++
+		n++
+	}
+	return n
+}
+
+// digits consumes up to max (unlimited if max <= 0) decimal digits and
+// returns them as a string.
+func (s *strptimeState) digits(max int) string {
+	start := s.pos
+	for s.pos < len(s.value) && (max <= 0 || s.pos-start < max) {
+		c := s.value[s.pos]
+		if c < '0' || c > '9' {
+			break
+		}
+		s.pos++
+	}
+	return s.value[start:s.pos]
+}
+
+// Strptime parses a string according to the directives in the format string
+// and returns the Vana'diel time it represents. It is the inverse of Strftime
+// and understands the same directives, flags and widths.
+//
+// Numerical fields may be padded with zeros or blanks and are read up to
+// their default width (or the explicitly given width), so both "%m" and
+// "%-m" accept "3" as well as "03". %Y, %C and %s accept an optional sign.
+// %A and %a match the full and abbreviated weekday name in any locale
+// registered by RegisterLocale, ignoring case. Any white space in the format matches
+// zero or more white space characters in the value; other text must match
+// exactly.
+//
+// Fields missing from the format default to the earliest possible value
+// (year 1, month 1, day 1, 00:00:00). When %y is given without %C, the
+// century is 0. %s sets the whole time from the number of seconds since
+// 0001-01-01 00:00:00 and only %N or %L may refine it. The day of the week
+// (%A, %a, %w) does not determine the date, but must match it.
+//
+// Out-of-range values, such as month 13 or day 31, are reported as errors.
+// The returned error is a *ParseError describing the offending directive and
+// its position in the value.
+func Strptime(format, value string) (Time, error) {
+	s := &strptimeState{format: format, value: value}
+
+	var (
+		year, century, yy     int64
+		mon, day, yday        int64
+		hour, min, sec, usec  int64
+		epoch                 int64
+		haveYear, haveCentury bool
+		haveYY, haveYday      bool
+		haveMon, haveDay      bool
+		haveEpoch             bool
+
+		// the %j field, to report a mismatch with the month and day
+		ydayDirective string
+		ydayOffset    int
+
+		// the last %A, %a or %w field, to report a mismatch with the date
+		wday          Weekday
+		haveWday      bool
+		wdayDirective string
+		wdayOffset    int
+	)
+	mon, day = 1, 1
+
+	format = normalizeFormat(format)
+	matches := strptimeDirective.FindAllStringSubmatchIndex(format, -1)
+	last := 0
+	for i, m := range matches {
+		if err := s.literal(format[last:m[0]]); err != nil {
+			return Time{}, err
+		}
+		last = m[1]
+
+		directive := format[m[0]:m[1]]
+		explicit := m[4] >= 0
+		var width int
+		if explicit {
+			width, _ = strconv.Atoi(format[m[4]:m[5]])
+		}
+		conversion := format[m[6]]
+
+		start := s.pos
+		switch conversion {
+		case 'n', 't':
+			s.spaces()
+			continue
+		case '%':
+			if err := s.literal("%"); err != nil {
+				return Time{}, err
+			}
+			continue
+		case 'A', 'a':
+			w, ok := s.weekday(conversion == 'a')
+			if !ok {
+				return Time{}, s.errorf(directive, start, "invalid weekday name")
+			}
+			wday, haveWday = w, true
+			wdayDirective, wdayOffset = directive, start
+			continue
+		}
+
+		// Blank padding counts towards the field width.
+		pad := s.skipSpaces()
+
+		if conversion == 'L' || conversion == 'N' {
+			// the digits are read as a fraction of a second
+			max := 0
+			if explicit {
+				max = width
+			}
+			ds := s.digits(max)
+			if ds == "" {
+				return Time{}, s.errorf(directive, start, "expected fractional seconds")
+			}
+			if len(ds) > 6 {
+				ds = ds[:6]
+			}
+			ds += strings.Repeat("0", 6-len(ds))
+			usec, _ = strconv.ParseInt(ds, 10, 64)
+			continue
+		}
+
+		signed := conversion == 'Y' || conversion == 'C' || conversion == 's'
+		max := width
+		switch {
+		case signed && !explicit:
+			// A number immediately followed by another numerical
+			// directive cannot be read greedily.
+			adjacent := i+1 < len(matches) && matches[i+1][0] == m[1] &&
+				strings.IndexByte(numericConversions, format[matches[i+1][6]]) >= 0
+			switch {
+			case adjacent && conversion == 'Y':
+				max = 4
+			case adjacent && conversion == 'C':
+				max = 2
+			}
+		case !signed:
+			if !explicit {
+				max = formatWidth(rune(conversion))
+			}
+			if max -= pad; max <= 0 {
+				max = 1
+			}
+		}
+
+		neg := false
+		if signed && s.pos < len(s.value) && (s.value[s.pos] == '-' || s.value[s.pos] == '+') {
+			neg = s.value[s.pos] == '-'
+			s.pos++
+		}
+		ds := s.digits(max)
+		if ds == "" {
+			return Time{}, s.errorf(directive, start, "expected number")
+		}
+		v, err := strconv.ParseInt(ds, 10, 64)
+		if err != nil {
+			return Time{}, s.errorf(directive, start, "number out of range")
+		}
+		if neg {
+			v = -v
+		}
+
+		check := func(name string, lo, hi int64) error {
+			if v < lo || v > hi {
+				return s.errorf(directive, start, name+" out of range")
+			}
+			return nil
+		}
+
+		switch conversion {
+		case 'Y':
+			year, haveYear = v, true
+		case 'C':
+			century, haveCentury = v, true
+		case 'y':
+			if err := check("year", 0, 99); err != nil {
+				return Time{}, err
+			}
+			yy, haveYY = v, true
+		case 'm':
+			if err := check("month", 1, 12); err != nil {
+				return Time{}, err
+			}
+			mon, haveMon = v, true
+		case 'd', 'e':
+			if err := check("day", 1, 30); err != nil {
+				return Time{}, err
+			}
+			day, haveDay = v, true
+		case 'j':
+			if err := check("day of year", 1, 360); err != nil {
+				return Time{}, err
+			}
+			yday, haveYday = v, true
+			ydayDirective, ydayOffset = directive, start
+		case 'H', 'k':
+			if err := check("hour", 0, 23); err != nil {
+				return Time{}, err
+			}
+			hour = v
+		case 'M':
+			if err := check("minute", 0, 59); err != nil {
+				return Time{}, err
+			}
+			min = v
+		case 'S':
+			if err := check("second", 0, 59); err != nil {
+				return Time{}, err
+			}
+			sec = v
+		case 'w':
+			if err := check("weekday", 0, 7); err != nil {
+				return Time{}, err
+			}
+			wday, haveWday = Weekday(v), true
+			wdayDirective, wdayOffset = directive, start
+		case 's':
+			epoch, haveEpoch = v, true
+		}
+	}
+	if err := s.literal(format[last:]); err != nil {
+		return Time{}, err
+	}
+	if s.pos < len(s.value) {
+		return Time{}, s.errorf("", s.pos, "extra text "+strconv.Quote(s.value[s.pos:]))
+	}
+
+	// the day of the week must match the date
+	checkWeekday := func(t Time) (Time, error) {
+		if haveWday && t.Weekday() != wday {
+			return Time{}, s.errorf(wdayDirective, wdayOffset, "weekday does not match the date")
+		}
+		return t, nil
+	}
+
+	if haveEpoch {
+		return checkWeekday(Time{epoch*int64(Second) + usec})
+	}
+
+	if !haveYear && (haveCentury || haveYY) {
+		year = century*100 + yy
+	} else if !haveYear {
+		year = 1
+	}
+
+	if haveYday {
+		ymon, yd := (yday-1)/30+1, (yday-1)%30+1
+		if (haveMon && ymon != mon) || (haveDay && yd != day) {
+			return Time{}, s.errorf(ydayDirective, ydayOffset, "day of year does not match month and day")
+		}
+		mon, day = ymon, yd
+	}
+
+	return checkWeekday(Date(int(year), int(mon), int(day), int(hour), int(min), int(sec), int(usec)))
+}
+
+// literal matches the literal text in the format. White space in the format
+// matches zero or more white space characters.
+func (s *strptimeState) literal(text string) error {
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		if unicode.IsSpace(r) {
+			text = text[size:]
+			s.spaces()
+			continue
+		}
+		if !strings.HasPrefix(s.value[s.pos:], string(r)) {
+			if s.pos >= len(s.value) {
+				return s.errorf("", s.pos, "unexpected end of value, expected "+strconv.Quote(text))
+			}
+			return s.errorf("", s.pos, "expected "+strconv.Quote(text))
+		}
+		s.pos += size
+		text = text[size:]
+	}
+	return nil
+}
+
+func (s *strptimeState) spaces() {
+	for s.pos < len(s.value) {
+		r, size := utf8.DecodeRuneInString(s.value[s.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		s.pos += size
+	}
+}
+
+// weekday consumes the longest weekday name of any supported locale and
+// returns its day of the week.
+func (s *strptimeState) weekday(abbr bool) (Weekday, bool) {
+	rest := s.value[s.pos:]
+	length := 0
+	var wday Weekday
+	try := func(names [8]string) {
+		for i, name := range names {
+			if len(name) > length && len(rest) >= len(name) && strings.EqualFold(rest[:len(name)], name) {
+				length = len(name)
+				wday = Weekday(i)
+			}
+		}
+	}
+	for _, c := range locales() {
+		if abbr {
+			try(c.WeekdayAbbrs)
+		} else {
+			try(c.Weekdays)
+		}
+	}
+	s.pos += length
+	return wday, length > 0
+}
@@ -0,0 +1,117 @@
+package vanatime_test
+
+import (
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+)
+
+func TestStrptime(t *testing.T) {
+	patterns := []struct {
+		Format string
+		Value  string
+		Want   vanatime.Time
+	}{
+		{"%Y-%m-%d %H:%M:%S", "1300-02-03 04:05:06", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+		{"%F %T", "1300-02-03 04:05:06", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+		{"%F %R", "1300-02-03 04:05", vanatime.Date(1300, 2, 3, 4, 5, 0, 0)},
+		{"%Y/%-m/%-d %X", "1300/2/3 04:05:06", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+		{"%Y/%_m/%e %k", "1300/ 2/ 3  4", vanatime.Date(1300, 2, 3, 4, 0, 0, 0)},
+		{"%Y%m%d%H%M%S", "13000203040506", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+		{"%Y-%j", "1300-033", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%C%y-%m-%d", "1300-02-03", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%Y-%m-%d", "-0001-02-03", vanatime.Date(-1, 2, 3, 0, 0, 0, 0)},
+		{"%T.%N", "04:05:06.123456", vanatime.Date(1, 1, 1, 4, 5, 6, 123456)},
+		{"%T.%3N", "04:05:06.123", vanatime.Date(1, 1, 1, 4, 5, 6, 123000)},
+		{"%T.%L", "04:05:06.123", vanatime.Date(1, 1, 1, 4, 5, 6, 123000)},
+		{"%T.%N", "04:05:06.123456789", vanatime.Date(1, 1, 1, 4, 5, 6, 123456)},
+		{"%F %A", "1300-02-03 Firesday", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%F %^A", "1300-02-03 FIRESDAY", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%F %A", "1300-02-03 火曜日", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%F (%w)", "1300-02-03 (0)", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%s", "40406860800", vanatime.Date(1300, 2, 3, 0, 0, 0, 0)},
+		{"%s.%N", "40406860800.500000", vanatime.Date(1300, 2, 3, 0, 0, 0, 500000)},
+		{"%F%n%T%%", "1300-02-03\t04:05:06%", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+		{"%F  %T", "1300-02-03 04:05:06", vanatime.Date(1300, 2, 3, 4, 5, 6, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got, err := vanatime.Strptime(pattern.Format, pattern.Value)
+		if err != nil {
+			t.Errorf("[%d]: %s", i, err)
+			continue
+		}
+
+		if !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got)
+		}
+	}
+}
+
+func TestStrptimeRoundTrip(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)
+	formats := []string{
+		"%Y-%m-%d %H:%M:%S.%N %A",
+		"%F %T.%6N",
+		"%C %y %j %k %M %S %N",
+		"%-d/%-m/%Y %H:%M:%S.%N",
+		"%s.%N",
+	}
+
+	for i, format := range formats {
+		s := vt.Strftime(format)
+		got, err := vanatime.Strptime(format, s)
+		if err != nil {
+			t.Errorf("[%d]: %s", i, err)
+			continue
+		}
+
+		if !got.Equal(vt) {
+			t.Errorf(`[%d]: want "%v", but "%v" (%q)`, i, vt, got, s)
+		}
+	}
+}
+
+func TestStrptimeError(t *testing.T) {
+	patterns := []struct {
+		Format    string
+		Value     string
+		Directive string
+		Offset    int
+	}{
+		{"%Y-%m-%d", "1300-13-01", "%m", 5},
+		{"%Y-%m-%d", "1300-12-31", "%d", 8},
+		{"%Y-%m-%d", "1300-00-01", "%m", 5},
+		{"%H:%M", "24:00", "%H", 0},
+		{"%H:%M", "12:60", "%M", 3},
+		{"%j", "361", "%j", 0},
+		{"%w", "8", "%w", 0},
+		{"%A", "Sunday", "%A", 0},
+		{"%Y-%m-%d", "1300/12/01", "", 4},
+		{"%Y-%m-%d", "1300-12", "", 7},
+		{"%Y-%m", "1300-12-01", "", 7},
+		{"%Y-%m-%d", "1300-xx-01", "%m", 5},
+		{"%Y-%j %m", "1300-033 03", "%j", 5},
+		{"%Y %3j %d", "1300 033 04", "%3j", 5},
+		{"%Y-%m-%d %A", "0001-01-01 Darksday", "%A", 11},
+		{"%F (%w)", "1300-02-03 (7)", "%w", 12},
+		{"%A %s", "Darksday 0", "%A", 0},
+	}
+
+	for i, pattern := range patterns {
+		_, err := vanatime.Strptime(pattern.Format, pattern.Value)
+		if err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+			continue
+		}
+
+		perr, ok := err.(*vanatime.ParseError)
+		if !ok {
+			t.Errorf("[%d]: want *ParseError, but %T", i, err)
+			continue
+		}
+		if perr.Directive != pattern.Directive || perr.Offset != pattern.Offset {
+			t.Errorf(`[%d]: want %q at %d, but %q at %d (%s)`, i, pattern.Directive, pattern.Offset, perr.Directive, perr.Offset, err)
+		}
+	}
+}
@@ -15,11 +15,16 @@ type Ticker struct {
 	earthTicker *time.Ticker
 	stop        chan struct{}
 	wg          sync.WaitGroup
+
+	// set if created by a ManualClock
+	manual *ManualClock
+	w      *manualWaiter
 }
 
 // NewTicker returns a new Ticker containing a channel that will send the
 // time with a period specified by the duration argument.
-// It adjusts the intervals or drops ticks to make up for slow receivers.
+// It adjusts the intervals or drops ticks to make up for slow receivers:
+// a tick is dropped if the channel still holds the previous one.
 // The duration d must be greater than zero; if not, NewTicker will panic.
 // Stop the ticker to release associated resources.
 func NewTicker(d Duration) *Ticker {
@@ -43,6 +48,7 @@ func NewTicker(d Duration) *Ticker {
 }
 
 func (t *Ticker) start() {
+	stop := t.stop
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
@@ -50,13 +56,13 @@ func (t *Ticker) start() {
 			select {
 			case value, ok := <-t.earthTicker.C:
 				if ok {
-					t.c <- FromEarth(value)
+					sendTime(t.c, FromEarth(value))
 				} else {
 					close(t.c)
 					return
 				}
 
-			case <-t.stop:
+			case <-stop:
 				return
 			}
 		}
@@ -67,12 +73,16 @@ func (t *Ticker) start() {
 // Stop does not close the channel, to prevent a concurrent goroutine
 // reading from the channel from seeing an erroneous "tick".
 func (t *Ticker) Stop() {
+	if t.manual != nil {
+		t.manual.stop(t.w)
+	}
 	if t.earthTicker != nil {
 		t.earthTicker.Stop()
-		if t.stop != nil {
-			close(t.stop)
-			t.wg.Wait()
-		}
+	}
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+		t.wg.Wait()
 	}
 }
 
@@ -87,3 +97,74 @@ func Tick(d Duration) <-chan Time {
 	}
 	return NewTicker(d).C
 }
+
+// NewAlignedTicker returns a new Ticker containing a channel that will send
+// every boundary time which is a multiple of unit, such as the start of
+// every Vana'diel hour for Hour or of every day for Day, beginning with the
+// first boundary after now. The time sent is the boundary itself rather than
+// the time the ticker woke up.
+//
+// Unlike NewTicker, the ticker does not drop ticks: every boundary is sent
+// exactly once and in order, and the ticker waits for slow receivers. The
+// wait for each boundary is computed from the current time, so the ticker
+// does not drift.
+// The unit must be greater than zero; if not, NewAlignedTicker will panic.
+// Stop the ticker to release associated resources.
+func NewAlignedTicker(unit Duration) *Ticker {
+	if unit <= 0 {
+		panic(errors.New("non-positive interval for NewAlignedTicker"))
+	}
+
+	c := make(chan Time, 1)
+	t := &Ticker{
+		C:    c,
+		c:    c,
+		stop: make(chan struct{}),
+	}
+
+	t.startAligned(unit, Now().Truncate(unit).Add(unit))
+
+	return t
+}
+
+func (t *Ticker) startAligned(unit Duration, next Time) {
+	stop := t.stop
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		timer := time.NewTimer(time.Until(next.Earth()))
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case <-stop:
+				return
+			}
+
+			// the timer may wake up slightly early, then wait again
+			if Now().Before(next) {
+				timer.Reset(time.Until(next.Earth()))
+				continue
+			}
+
+			select {
+			case t.c <- next:
+			case <-stop:
+				return
+			}
+			next = next.Add(unit)
+			timer.Reset(time.Until(next.Earth()))
+		}
+	}()
+}
+
+// AlignedTick is a convenience wrapper for NewAlignedTicker providing access
+// to the ticking channel only. Like Tick, the underlying Ticker cannot be
+// recovered by the garbage collector.
+// AlignedTick will return nil if unit <= 0.
+func AlignedTick(unit Duration) <-chan Time {
+	if unit <= 0 {
+		return nil
+	}
+	return NewAlignedTicker(unit).C
+}
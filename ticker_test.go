@@ -69,3 +69,51 @@ func TestNewTickerLtZeroDuration(t *testing.T) {
 	}()
 	NewTicker(-1)
 }
+
+func TestAlignedTicker(t *testing.T) {
+	// a Vana'diel second is 40ms on the Earth
+	unit := Second
+	ticker := NewAlignedTicker(unit)
+	defer ticker.Stop()
+
+	var prev Time
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			// slow receivers do not lose ticks
+			Sleep(3 * unit)
+		}
+		v := <-ticker.C
+		if now := Now(); now.Before(v) {
+			t.Fatalf("[%d]: tick %v received at %v", i, v, now)
+		}
+		if !v.Equal(v.Truncate(unit)) {
+			t.Errorf("[%d]: tick %v is not aligned to %v", i, v, unit)
+		}
+		if i > 0 && v.Sub(prev) != unit {
+			t.Errorf("[%d]: tick %v after %v", i, v, prev)
+		}
+		prev = v
+	}
+}
+
+func TestAlignedTickerStop(t *testing.T) {
+	ticker := NewAlignedTicker(Second)
+	<-ticker.C
+	ticker.Stop()
+	Sleep(3 * Second)
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case <-ticker.C:
+		t.Fatal("Ticker did not shut down")
+	default:
+	}
+}
+
+func TestAlignedTick(t *testing.T) {
+	if got := AlignedTick(-1); got != nil {
+		t.Errorf("AlignedTick(-1) = %v; want nil", got)
+	}
+}
@@ -51,6 +51,12 @@ const (
 	MoonCycleDays     int   = 84 // Vana'diel moon cycle lasts 84 days
 )
 
+// EarthPrecision is the Earth duration of one Vana'diel microsecond (40ns).
+// Earth times are converted to Vana'diel time by rounding down to a
+// multiple of EarthPrecision, so an Earth time maps to the Vana'diel
+// microsecond in progress at that instant.
+const EarthPrecision time.Duration = time.Microsecond / time.Duration(TimeScale)
+
 // A Time represents an instant in Vana'diel time with microsecond precision.
 type Time struct {
 	// the time as microseconds since C.E. 0001-01-01 00:00:00
@@ -83,10 +89,21 @@ func Date(year, mon, day, hour, min, sec, usec int) Time {
 }
 
 // FromEarth returns the Time corresponding to the given Earth time.
+// The Earth time is rounded down (toward the past) to the Vana'diel
+// microsecond; see EarthPrecision. For any Time t, FromEarth(t.Earth())
+// returns t.
 func FromEarth(earth time.Time) Time {
 	return earth2vana(earth)
 }
 
+// FromEarthExact is like FromEarth but also reports whether the Earth time
+// is exactly the start of the returned Vana'diel microsecond, that is,
+// whether no rounding took place.
+func FromEarthExact(earth time.Time) (t Time, exact bool) {
+	t = earth2vana(earth)
+	return t, t.Earth().Equal(earth)
+}
+
 // FromInt64 returns the Time corresponding to the given Vana'diel time (since C.E. 0001-01-01 00:00:00).
 func FromInt64(time int64) Time {
 	return Time{
@@ -131,6 +148,7 @@ func lessThanHalf(x, y Duration) bool {
 }
 
 // Truncate returns the result of rounding t down to a multiple of d.
+// Times before the zero time are rounded down too, toward the past.
 //
 // Truncate operates on the time as an absolute duration since the zero
 // time; it does not operate on the presentation form of the time. Thus,
@@ -139,7 +157,7 @@ func (t Time) Truncate(d Duration) Time {
 	if d <= 0 {
 		return t
 	}
-	r := Duration(t.time % int64(d))
+	r := Duration(floorMod(t.time, int64(d)))
 	return t.Add(-r)
 }
 
@@ -153,7 +171,7 @@ func (t Time) Round(d Duration) Time {
 	if d <= 0 {
 		return t
 	}
-	r := Duration(t.time % int64(d))
+	r := Duration(floorMod(t.time, int64(d)))
 	if lessThanHalf(r, d) {
 		return t.Add(-r)
 	}
@@ -175,16 +193,25 @@ func (t Time) Sub(u Time) Duration {
 	}
 }
 
-// Earth returns the time of Earth.
+// Earth returns the time of Earth, in the local time zone.
+// The result is the first Earth instant of the Vana'diel microsecond t,
+// with nanosecond precision.
 func (t Time) Earth() time.Time {
 	return vana2earth(t)
 }
 
+// EarthRange returns the half-open interval [start, end) of Earth times
+// which FromEarth maps to t. Its length is EarthPrecision.
+func (t Time) EarthRange() (start, end time.Time) {
+	start = vana2earth(t)
+	return start, start.Add(EarthPrecision)
+}
+
 // Date returns the year, month, day and day of the year in which t occurs.
 func (t Time) Date() (year, mon, day, yday int) {
-	year = int(t.time/int64(Year)) + 1
-	mon = int(t.time%int64(Year)/int64(Month)) + 1
-	day = int(t.time%int64(Month)/int64(Day)) + 1
+	year = int(floorDiv(t.time, int64(Year))) + 1
+	mon = int(floorMod(t.time, int64(Year))/int64(Month)) + 1
+	day = int(floorMod(t.time, int64(Month))/int64(Day)) + 1
 	yday = (mon-1)*30 + day
 	return
 }
@@ -215,15 +242,15 @@ func (t Time) YearDay() int {
 
 // Weekday returns the day of the week specified by t.
 func (t Time) Weekday() Weekday {
-	wday := int(t.time % int64(Week) / int64(Day))
+	wday := int(floorMod(t.time, int64(Week)) / int64(Day))
 	return Weekday(wday)
 }
 
 // Clock returns the hour, minute, and second within the day specified by t.
 func (t Time) Clock() (hour, min, sec int) {
-	hour = int(t.time % int64(Day) / int64(Hour))
-	min = int(t.time % int64(Hour) / int64(Minute))
-	sec = int(t.time % int64(Minute) / int64(Second))
+	hour = int(floorMod(t.time, int64(Day)) / int64(Hour))
+	min = int(floorMod(t.time, int64(Hour)) / int64(Minute))
+	sec = int(floorMod(t.time, int64(Minute)) / int64(Second))
 	return
 }
 
@@ -247,7 +274,7 @@ func (t Time) Second() int {
 
 // Microsecond returns the microsecond offset within the second specified by t, in the range [0, 999999].
 func (t Time) Microsecond() int {
-	return int(t.time % int64(Second))
+	return int(floorMod(t.time, int64(Second)))
 }
 
 // Int64 returns t as a int64 since C.E. 0001-01-01 00:00:00.
@@ -257,8 +284,8 @@ func (t Time) Int64() int64 {
 
 // Moon returns the moon specified by t.
 func (t Time) Moon() Moon {
-	var days int = int(t.time / int64(Day))
-	timeOfMoon := (((int64(days) + 12) % 7) * int64(Day)) + (t.time % int64(Day))
+	var days int = int(floorDiv(t.time, int64(Day)))
+	timeOfMoon := floorMod(int64(days)+12, 7)*int64(Day) + floorMod(t.time, int64(Day))
 
 	return Moon{
 		days:       days,
@@ -273,21 +300,51 @@ func (t Time) String() string {
 	return t.Strftime("%Y-%m-%d %H:%M:%S") + " " + t.Weekday().String() + " " + m.String()
 }
 
+// StringLocale is like String but returns the names of the day and the moon
+// phase by specified locale.
+func (t Time) StringLocale(locale string) string {
+	m := t.Moon()
+	return t.Strftime("%Y-%m-%d %H:%M:%S") + " " + t.Weekday().StringLocale(locale) + " " + m.StringLocale(locale)
+}
+
 func earth2vana(etime time.Time) Time {
-	return Time{e2v(etime.UnixNano() / 1000)}
+	nsec := int64(etime.Nanosecond())
+	usec := etime.Unix()*int64(Second) + nsec/1000
+	return Time{e2v(usec, nsec%1000)}
 }
 
-func e2v(etime int64) int64 {
-	return (etime+VanaEarthDiffTime)*int64(TimeScale) - int64(Year)
+// e2v converts Earth microseconds since the Unix epoch plus the remaining
+// nanoseconds to Vana'diel microseconds, rounding down.
+func e2v(etime, nsec int64) int64 {
+	return (etime+VanaEarthDiffTime)*int64(TimeScale) - int64(Year) + nsec/int64(EarthPrecision)
 }
 
 func vana2earth(vtime Time) time.Time {
-	usec := v2e(vtime.time)
-	return time.Unix(usec/int64(Second), usec%int64(Second))
+	usec, nsec := v2e(vtime.time)
+	return time.Unix(floorDiv(usec, int64(Second)), floorMod(usec, int64(Second))*1000+nsec)
+}
+
+// v2e converts Vana'diel microseconds to Earth microseconds since the Unix
+// epoch plus the remaining nanoseconds.
+func v2e(vtime int64) (usec, nsec int64) {
+	n := vtime + int64(Year)
+	usec = floorDiv(n, int64(TimeScale)) - VanaEarthDiffTime
+	nsec = floorMod(n, int64(TimeScale)) * int64(EarthPrecision)
+	return
+}
+
+// floorDiv returns x/y rounded toward negative infinity.
+func floorDiv(x, y int64) int64 {
+	q := x / y
+	if (x%y != 0) && ((x < 0) != (y < 0)) {
+		q--
+	}
+	return q
 }
 
-func v2e(vtime int64) int64 {
-	return ((vtime + int64(Year)) / int64(TimeScale)) - VanaEarthDiffTime
+// floorMod returns x modulo y with the sign of y.
+func floorMod(x, y int64) int64 {
+	return x - floorDiv(x, y)*y
 }
 
 // from https://golang.org/src/time/time.go
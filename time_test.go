@@ -95,6 +95,25 @@ func TestVanaVariation(t *testing.T) {
 	}
 }
 
+func TestStrftimeEpochSeconds(t *testing.T) {
+	patterns := []struct {
+		Time vanatime.Time
+		Want string
+	}{
+		{vanatime.Date(1, 1, 1, 0, 0, 0, 0), "0"},
+		{vanatime.Date(1, 1, 1, 0, 0, 1, 999999), "1"},
+		{vanatime.Date(1313, 4, 13, 21, 20, 27, 654321), "40817337627"},
+		// rounded down, like Strptime reads it back with %N
+		{vanatime.Date(0, 12, 30, 23, 59, 59, 500000), "-1"},
+	}
+
+	for i, pattern := range patterns {
+		if got := pattern.Time.Strftime("%s"); got != pattern.Want {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want, got)
+		}
+	}
+}
+
 func TestAdd(t *testing.T) {
 	vt := vanatime.Date(1000, 3, 1, 0, 0, 0, 0)
 	want := vanatime.Date(1000, 3, 8, 12, 34, 56, 0)
@@ -164,6 +183,61 @@ func TestRound(t *testing.T) {
 	}
 }
 
+func TestTruncateRoundNegative(t *testing.T) {
+	vt := vanatime.Date(0, 12, 30, 12, 29, 0, 0) // the last day before the zero time
+	patterns := []struct {
+		Got  vanatime.Time
+		Want vanatime.Time
+	}{
+		{vt.Truncate(vanatime.Day), vanatime.Date(0, 12, 30, 0, 0, 0, 0)},
+		{vt.Truncate(vanatime.Hour), vanatime.Date(0, 12, 30, 12, 0, 0, 0)},
+		{vt.Truncate(vanatime.Week), vanatime.Date(0, 12, 23, 0, 0, 0, 0)},
+		{vt.Truncate(vanatime.Year), vanatime.Date(0, 1, 1, 0, 0, 0, 0)},
+		{vt.Round(vanatime.Hour), vanatime.Date(0, 12, 30, 12, 0, 0, 0)},
+		{vt.Round(vanatime.Day), vanatime.Date(1, 1, 1, 0, 0, 0, 0)},
+		{vt.Add(-vanatime.Hour).Round(vanatime.Day), vanatime.Date(0, 12, 30, 0, 0, 0, 0)},
+		{vanatime.NextDayOfElement(vt, vanatime.ElementFire), vanatime.Date(1, 1, 1, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		if !pattern.Got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want, pattern.Got)
+		}
+	}
+}
+
+func TestDateNegative(t *testing.T) {
+	patterns := []struct {
+		T       vanatime.Time
+		Date    [3]int
+		Clock   [3]int
+		Usec    int
+		Weekday vanatime.Weekday
+	}{
+		{vanatime.Date(0, 12, 30, 23, 59, 59, 999999), [3]int{0, 12, 30}, [3]int{23, 59, 59}, 999999, vanatime.Darksday},
+		{vanatime.Date(1, 1, 1, 0, 0, 0, -1), [3]int{0, 12, 30}, [3]int{23, 59, 59}, 999999, vanatime.Darksday},
+		{vanatime.Date(0, 1, 1, 0, 0, 0, 0), [3]int{0, 1, 1}, [3]int{0, 0, 0}, 0, vanatime.Firesday},
+		{vanatime.Date(-1, 6, 15, 6, 30, 0, 0), [3]int{-1, 6, 15}, [3]int{6, 30, 0}, 0, vanatime.Iceday},
+	}
+
+	for i, pattern := range patterns {
+		year, mon, day, _ := pattern.T.Date()
+		hour, min, sec := pattern.T.Clock()
+		if got := [3]int{year, mon, day}; got != pattern.Date {
+			t.Errorf("[%d]: want date %v, but %v", i, pattern.Date, got)
+		}
+		if got := [3]int{hour, min, sec}; got != pattern.Clock {
+			t.Errorf("[%d]: want clock %v, but %v", i, pattern.Clock, got)
+		}
+		if got := pattern.T.Microsecond(); got != pattern.Usec {
+			t.Errorf("[%d]: want microsecond %d, but %d", i, pattern.Usec, got)
+		}
+		if got := pattern.T.Weekday(); got != pattern.Weekday {
+			t.Errorf("[%d]: want %v, but %v", i, pattern.Weekday, got)
+		}
+	}
+}
+
 func TestSub(t *testing.T) {
 	vt := vanatime.Date(650, 3, 11, 12, 34, 56, 0)
 	patterns := []struct {
@@ -188,3 +262,69 @@ func TestSub(t *testing.T) {
 		}
 	}
 }
+
+func TestEarthRoundTrip(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 0)
+	for i := 0; i < 1000; i++ {
+		vt = vt.Add(vanatime.Duration(i*7919) * vanatime.Microsecond)
+		got := vanatime.FromEarth(vt.Earth())
+		if !got.Equal(vt) {
+			t.Fatalf(`[%d]: want "%s", but "%s"`, i, vt.Format(vanatime.DateTimeMicro), got.Format(vanatime.DateTimeMicro))
+		}
+	}
+
+	for _, vt := range []vanatime.Time{
+		vanatime.Date(1, 1, 1, 0, 0, 0, 1),
+		vanatime.Date(-1, 12, 30, 23, 59, 59, 999999),
+	} {
+		if got := vanatime.FromEarth(vt.Earth()); !got.Equal(vt) {
+			t.Errorf(`want "%s", but "%s"`, vt.Format(vanatime.DateTimeMicro), got.Format(vanatime.DateTimeMicro))
+		}
+	}
+}
+
+func TestEarthSubsecond(t *testing.T) {
+	vt := vanatime.Date(1, 1, 1, 0, 0, 1, 1)
+	got := vt.Earth()
+	want := time.Date(1967, 2, 10, 0, 0, 0, 40000040, locJA)
+
+	if !got.Equal(want) {
+		t.Fatalf("want %v, but %v:", want, got)
+	}
+}
+
+func TestEarthRange(t *testing.T) {
+	vt := vanatime.Date(1313, 4, 13, 21, 20, 27, 654321)
+	start, end := vt.EarthRange()
+
+	if vanatime.EarthPrecision != 40*time.Nanosecond {
+		t.Fatalf("want %v, but %v", 40*time.Nanosecond, vanatime.EarthPrecision)
+	}
+	if got := end.Sub(start); got != vanatime.EarthPrecision {
+		t.Fatalf("want %v, but %v", vanatime.EarthPrecision, got)
+	}
+
+	patterns := []struct {
+		E     time.Time
+		Want  vanatime.Time
+		Exact bool
+	}{
+		{start, vt, true},
+		{start.Add(time.Nanosecond), vt, false},
+		{end.Add(-time.Nanosecond), vt, false},
+		{end, vt.Add(vanatime.Microsecond), true},
+		{start.Add(-time.Nanosecond), vt.Add(-vanatime.Microsecond), false},
+	}
+
+	for i, pattern := range patterns {
+		got, exact := vanatime.FromEarthExact(pattern.E)
+		if !got.Equal(pattern.Want) || exact != pattern.Exact {
+			t.Errorf(`[%d]: want "%s" (exact=%v), but "%s" (exact=%v)`, i,
+				pattern.Want.Format(vanatime.DateTimeMicro), pattern.Exact,
+				got.Format(vanatime.DateTimeMicro), exact)
+		}
+		if got := vanatime.FromEarth(pattern.E); !got.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%s", but "%s"`, i, pattern.Want.Format(vanatime.DateTimeMicro), got.Format(vanatime.DateTimeMicro))
+		}
+	}
+}
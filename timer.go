@@ -8,7 +8,7 @@ import (
 // The Timer type represents a single event.
 // When the Timer expires, the current time will be sent on C,
 // unless the Timer was created by AfterFunc.
-// A Timer must be created with NewTimer or AfterFunc.
+// A Timer must be created with NewTimer or AfterFunc, or by a Clock.
 type Timer struct {
 	C <-chan Time
 
@@ -17,16 +17,31 @@ type Timer struct {
 	earthTimer *time.Timer
 	stop       chan struct{}
 	wg         sync.WaitGroup
+
+	deadline Time
+	absolute bool // created with NewTimerAt or AfterFuncAt
+
+	// set if created by a ManualClock
+	manual *ManualClock
+	w      *manualWaiter
 }
 
 type timerFunc func(c chan<- Time, t Time)
 
+// sendTime does a non-blocking send of the current time on c.
+func sendTime(c chan<- Time, t Time) {
+	select {
+	case c <- t:
+	default:
+	}
+}
+
 // NewTimer creates a new Timer that will send
 // the current time on its channel after at least duration d.
+// The send does not block: if the channel is full, as when the timer is
+// Reset without draining the channel, the time is dropped.
 func NewTimer(d Duration) *Timer {
-	return newTimer(d, func(c chan<- Time, t Time) {
-		c <- t
-	})
+	return newTimer(d, sendTime)
 }
 
 func newTimer(d Duration, f timerFunc) *Timer {
@@ -38,6 +53,36 @@ func newTimer(d Duration, f timerFunc) *Timer {
 		c:          c,
 		f:          f,
 		earthTimer: earthTimer,
+		deadline:   Now().Add(d),
+	}
+
+	t.start()
+
+	return t
+}
+
+// NewTimerAt creates a new Timer that will send the current time on its
+// channel at the Vana'diel time t. If t is not after the current time, the
+// timer fires immediately.
+//
+// Unlike NewTimer, the wait is computed from the exact Earth time of t and
+// the remaining time is computed again when the timer wakes up, so the timer
+// does not fire early even for long waits or if the system clock changes.
+func NewTimerAt(t Time) *Timer {
+	return newTimerAt(t, sendTime)
+}
+
+func newTimerAt(deadline Time, f timerFunc) *Timer {
+	earthTimer := time.NewTimer(time.Until(deadline.Earth()))
+
+	c := make(chan Time, 1)
+	t := &Timer{
+		C:          c,
+		c:          c,
+		f:          f,
+		earthTimer: earthTimer,
+		deadline:   deadline,
+		absolute:   true,
 	}
 
 	t.start()
@@ -47,26 +92,44 @@ func newTimer(d Duration, f timerFunc) *Timer {
 
 func (t *Timer) start() {
 	t.stop = make(chan struct{})
+	stop := t.stop
+	deadline, absolute := t.deadline, t.absolute
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
 		for {
 			select {
 			case value, ok := <-t.earthTimer.C:
-				if ok {
-					t.f(t.c, FromEarth(value))
-				} else {
+				if !ok {
 					close(t.c)
+					return
+				}
+				if absolute {
+					if d := time.Until(deadline.Earth()); d > 0 {
+						t.earthTimer.Reset(d)
+						continue
+					}
 				}
+				t.f(t.c, FromEarth(value))
 				return
 
-			case <-t.stop:
+			case <-stop:
 				return
 			}
 		}
 	}()
 }
 
+// Deadline returns the time at which the timer fires, or fired. For timers
+// created with a duration, it is the time of the creation or the last Reset
+// plus the duration.
+func (t *Timer) Deadline() Time {
+	if t.manual != nil {
+		return t.manual.deadline(t.w)
+	}
+	return t.deadline
+}
+
 // Stop prevents the Timer from firing.
 // It returns true if the call stops the timer, false if the timer has already
 // expired or been stopped.
@@ -90,6 +153,10 @@ func (t *Timer) start() {
 // If the caller needs to know whether f is completed, it must coordinate
 // with f explicitly.
 func (t *Timer) Stop() bool {
+	if t.manual != nil {
+		return t.manual.stop(t.w)
+	}
+
 	active := false
 	if t.earthTimer != nil {
 		active = t.earthTimer.Stop()
@@ -128,7 +195,13 @@ func (t *Timer) Stop() bool {
 // Reset should always be invoked on stopped or expired channels, as described above.
 // The return value exists to preserve compatibility with existing programs.
 func (t *Timer) Reset(d Duration) bool {
+	if t.manual != nil {
+		return t.manual.reset(t.w, d)
+	}
+
 	r := t.earthTimer.Reset(vd2ed(d))
+	t.deadline = Now().Add(d)
+	t.absolute = false
 	t.start()
 	return r
 }
@@ -142,6 +215,15 @@ func AfterFunc(d Duration, f func()) *Timer {
 	})
 }
 
+// AfterFuncAt waits until the Vana'diel time t and then calls f in its own
+// goroutine, as NewTimerAt does. It returns a Timer that can be used to
+// cancel the call using its Stop method.
+func AfterFuncAt(t Time, f func()) *Timer {
+	return newTimerAt(t, func(c chan<- Time, t Time) {
+		go f()
+	})
+}
+
 // After waits for the duration to elapse and then sends the current time
 // on the returned channel.
 // It is equivalent to NewTimer(d).C.
@@ -29,6 +29,21 @@ func TestAfterFunc(t *testing.T) {
 	<-c
 }
 
+func TestTimerResetUndrained(t *testing.T) {
+	timer := NewTimer(1)
+	Sleep(Second)
+	// the first time is not received, so the second one is dropped
+	timer.Reset(1)
+	Sleep(Second)
+	timer.Stop()
+	<-timer.C
+	select {
+	case <-timer.C:
+		t.Fatal("dropped time was received")
+	default:
+	}
+}
+
 func TestAfterStress(t *testing.T) {
 	stop := uint32(0)
 	go func() {
@@ -47,3 +62,61 @@ func TestAfterStress(t *testing.T) {
 	ticker.Stop()
 	atomic.StoreUint32(&stop, 1)
 }
+
+func TestNewTimerAt(t *testing.T) {
+	// a Vana'diel second is 40ms on the Earth
+	deadline := Now().Add(2 * Second)
+	timer := NewTimerAt(deadline)
+	if got := timer.Deadline(); !got.Equal(deadline) {
+		t.Errorf(`want "%v", but "%v"`, deadline, got)
+	}
+	v := <-timer.C
+	if v.Before(deadline) {
+		t.Errorf(`fired at "%v", before "%v"`, v, deadline)
+	}
+	if now := Now(); now.Before(deadline) {
+		t.Errorf(`received at "%v", before "%v"`, now, deadline)
+	}
+
+	// the past fires immediately
+	timer = NewTimerAt(deadline.Add(-Day))
+	select {
+	case <-timer.C:
+	case <-After(Hour):
+		t.Error("timer did not fire")
+	}
+}
+
+func TestAfterFuncAt(t *testing.T) {
+	deadline := Now().Add(2 * Second)
+	c := make(chan Time)
+	AfterFuncAt(deadline, func() {
+		c <- Now()
+	})
+	if v := <-c; v.Before(deadline) {
+		t.Errorf(`called at "%v", before "%v"`, v, deadline)
+	}
+
+	timer := AfterFuncAt(Now().Add(Hour), func() {
+		t.Error("stopped timer called the function")
+	})
+	if !timer.Stop() {
+		t.Error("Stop returned false")
+	}
+}
+
+func TestTimerDeadline(t *testing.T) {
+	start := Now()
+	timer := NewTimer(Hour)
+	defer timer.Stop()
+	if d := timer.Deadline().Sub(start); d < Hour || d > Hour+Minute {
+		t.Errorf("deadline %v after the creation", d)
+	}
+
+	timer.Stop()
+	start = Now()
+	timer.Reset(Day)
+	if d := timer.Deadline().Sub(start); d < Day || d > Day+Minute {
+		t.Errorf("deadline %v after the reset", d)
+	}
+}
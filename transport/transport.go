@@ -0,0 +1,177 @@
+// Package transport computes the departures of the scheduled transport
+// services in Vana'diel, such as the airships, the ferry and the barges,
+// from timetables supplied by the program.
+//
+// Services depart at fixed Vana'diel clock times every day. The package
+// includes no timetable; programs build Route values, or Load them from a
+// data file in JSON, with the departures as offsets from 00:00 and the
+// travel time in the format of vanatime.ParseDuration:
+//
+//     {"routes": [
+//         {"name": "Ferry", "from": "Selbina", "to": "Mhaura",
+//          "departures": ["0s", "8h", "16h"], "travel": "6h"},
+//         ...
+//     ]}
+//
+// and assign them to Routes to be searched by Find.
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// A Route is a scheduled transport service between two places.
+type Route struct {
+	Name string `json:"name"` // name of the service, e.g. "Airship"
+	From string `json:"from"` // place of departure
+	To   string `json:"to"`   // place of arrival
+
+	// Departures are the departure times within a Vana'diel day, as
+	// offsets from 00:00 in ascending order.
+	Departures []vanatime.Duration `json:"departures"`
+
+	// Travel is the time from departure to arrival.
+	Travel vanatime.Duration `json:"travel"`
+}
+
+// A Departure is a single departure of a route.
+type Departure struct {
+	Route  *Route
+	Depart vanatime.Time
+	Arrive vanatime.Time
+}
+
+// DepartEarth returns the Earth time of the departure.
+func (d Departure) DepartEarth() time.Time {
+	return d.Depart.Earth()
+}
+
+// ArriveEarth returns the Earth time of the arrival.
+func (d Departure) ArriveEarth() time.Time {
+	return d.Arrive.Earth()
+}
+
+// Clock returns the offset from 00:00 of the given Vana'diel clock time,
+// for use in Route.Departures.
+func Clock(hour, min int) vanatime.Duration {
+	return vanatime.Duration(hour)*vanatime.Hour + vanatime.Duration(min)*vanatime.Minute
+}
+
+// Next returns the first departure of r at or after t.
+// It panics if r has no departures.
+func (r *Route) Next(t vanatime.Time) Departure {
+	if len(r.Departures) == 0 {
+		panic("transport: route " + r.String() + " has no departures")
+	}
+
+	day := t.Truncate(vanatime.Day)
+	for {
+		for _, offset := range r.Departures {
+			depart := day.Add(offset)
+			if !depart.Before(t) {
+				return Departure{
+					Route:  r,
+					Depart: depart,
+					Arrive: depart.Add(r.Travel),
+				}
+			}
+		}
+		day = day.Add(vanatime.Day)
+	}
+}
+
+// NextN returns the next n departures of r at or after t, or nil if
+// n <= 0.
+func (r *Route) NextN(t vanatime.Time, n int) []Departure {
+	if n <= 0 {
+		return nil
+	}
+	deps := make([]Departure, 0, n)
+	for i := 0; i < n; i++ {
+		d := r.Next(t)
+		deps = append(deps, d)
+		t = d.Depart.Add(vanatime.Microsecond)
+	}
+	return deps
+}
+
+// String returns the route in the form "Airship: Port Jeuno -> Port Bastok".
+func (r *Route) String() string {
+	return r.Name + ": " + r.From + " -> " + r.To
+}
+
+// NextDepartures returns the next n departures at or after t among the
+// given routes, ordered by departure time, or nil if n <= 0.
+func NextDepartures(t vanatime.Time, n int, routes []*Route) []Departure {
+	if n <= 0 {
+		return nil
+	}
+	var deps []Departure
+	for _, r := range routes {
+		deps = append(deps, r.NextN(t, n)...)
+	}
+	sort.SliceStable(deps, func(i, j int) bool {
+		return deps[i].Depart.Before(deps[j].Depart)
+	})
+	if len(deps) > n {
+		deps = deps[:n]
+	}
+	return deps
+}
+
+// Find returns the routes in Routes which depart from and arrive at the
+// given places. The names are matched case-insensitively by prefix, and an
+// empty name matches any place.
+func Find(from, to string) []*Route {
+	var found []*Route
+	for _, r := range Routes {
+		if matchPlace(r.From, from) && matchPlace(r.To, to) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func matchPlace(place, name string) bool {
+	return len(name) <= len(place) && strings.EqualFold(place[:len(name)], name)
+}
+
+// Routes is the timetable searched by Find. It is empty until the program
+// assigns the routes, such as those returned by Load.
+var Routes []*Route
+
+// Load reads routes in the JSON format described in the package
+// documentation.
+func Load(r io.Reader) ([]*Route, error) {
+	var file struct {
+		Routes []*Route `json:"routes"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("transport: %v", err)
+	}
+	for _, r := range file.Routes {
+		if r.Name == "" || r.From == "" || r.To == "" {
+			return nil, errors.New("transport: route without a name or places")
+		}
+		if len(r.Departures) == 0 {
+			return nil, errors.New("transport: route " + r.String() + " has no departures")
+		}
+		for i, d := range r.Departures {
+			if d < 0 || d >= vanatime.Day || (i > 0 && d <= r.Departures[i-1]) {
+				return nil, errors.New("transport: route " + r.String() + " has invalid departures")
+			}
+		}
+		if r.Travel <= 0 {
+			return nil, errors.New("transport: route " + r.String() + " has no travel time")
+		}
+	}
+	return file.Routes, nil
+}
@@ -0,0 +1,165 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/transport"
+)
+
+var ferry = &transport.Route{
+	Name:       "Ferry",
+	From:       "Selbina",
+	To:         "Mhaura",
+	Departures: []vanatime.Duration{transport.Clock(0, 0), transport.Clock(8, 0), transport.Clock(16, 0)},
+	Travel:     transport.Clock(6, 0),
+}
+
+func TestRouteNext(t *testing.T) {
+	patterns := []struct {
+		T    vanatime.Time
+		Want vanatime.Time
+	}{
+		{vanatime.Date(1313, 4, 13, 0, 0, 0, 0), vanatime.Date(1313, 4, 13, 0, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 13, 0, 0, 0, 1), vanatime.Date(1313, 4, 13, 8, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 13, 12, 34, 0, 0), vanatime.Date(1313, 4, 13, 16, 0, 0, 0)},
+		{vanatime.Date(1313, 4, 13, 16, 0, 1, 0), vanatime.Date(1313, 4, 14, 0, 0, 0, 0)},
+		{vanatime.Date(1313, 12, 30, 23, 0, 0, 0), vanatime.Date(1314, 1, 1, 0, 0, 0, 0)},
+	}
+
+	for i, pattern := range patterns {
+		got := ferry.Next(pattern.T)
+		if !got.Depart.Equal(pattern.Want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want, got.Depart)
+		}
+		if want := pattern.Want.Add(6 * vanatime.Hour); !got.Arrive.Equal(want) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want, got.Arrive)
+		}
+		if !got.DepartEarth().Equal(pattern.Want.Earth()) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, pattern.Want.Earth(), got.DepartEarth())
+		}
+		if got.Route != ferry {
+			t.Errorf(`[%d]: want %v, but %v`, i, ferry, got.Route)
+		}
+	}
+}
+
+func TestRouteNextN(t *testing.T) {
+	deps := ferry.NextN(vanatime.Date(1313, 4, 13, 12, 0, 0, 0), 4)
+	want := []vanatime.Time{
+		vanatime.Date(1313, 4, 13, 16, 0, 0, 0),
+		vanatime.Date(1313, 4, 14, 0, 0, 0, 0),
+		vanatime.Date(1313, 4, 14, 8, 0, 0, 0),
+		vanatime.Date(1313, 4, 14, 16, 0, 0, 0),
+	}
+
+	if len(deps) != len(want) {
+		t.Fatalf("want %d departures, but %d", len(want), len(deps))
+	}
+	for i := range want {
+		if !deps[i].Depart.Equal(want[i]) {
+			t.Errorf(`[%d]: want "%v", but "%v"`, i, want[i], deps[i].Depart)
+		}
+	}
+
+	if deps := ferry.NextN(vanatime.Date(1313, 4, 13, 12, 0, 0, 0), -1); deps != nil {
+		t.Errorf("want no departures, but %v", deps)
+	}
+}
+
+func TestNextDepartures(t *testing.T) {
+	barge := &transport.Route{
+		Name:       "Barge",
+		From:       "South Landing",
+		To:         "North Landing",
+		Departures: []vanatime.Duration{transport.Clock(10, 0)},
+		Travel:     transport.Clock(9, 0),
+	}
+
+	deps := transport.NextDepartures(vanatime.Date(1313, 4, 13, 7, 0, 0, 0), 3, []*transport.Route{ferry, barge})
+	want := []struct {
+		Route *transport.Route
+		T     vanatime.Time
+	}{
+		{ferry, vanatime.Date(1313, 4, 13, 8, 0, 0, 0)},
+		{barge, vanatime.Date(1313, 4, 13, 10, 0, 0, 0)},
+		{ferry, vanatime.Date(1313, 4, 13, 16, 0, 0, 0)},
+	}
+
+	if len(deps) != len(want) {
+		t.Fatalf("want %d departures, but %d", len(want), len(deps))
+	}
+	for i := range want {
+		if deps[i].Route != want[i].Route || !deps[i].Depart.Equal(want[i].T) {
+			t.Errorf(`[%d]: want %v at "%v", but %v at "%v"`, i, want[i].Route, want[i].T, deps[i].Route, deps[i].Depart)
+		}
+	}
+	if deps := transport.NextDepartures(vanatime.Date(1313, 4, 13, 7, 0, 0, 0), 0, []*transport.Route{ferry, barge}); deps != nil {
+		t.Errorf("want no departures, but %v", deps)
+	}
+}
+
+// demo timetable, not the timetable of the game
+const testRoutes = `{"routes": [
+	{"name": "Airship", "from": "Port Jeuno", "to": "Port Bastok", "departures": ["1h40m", "7h40m", "13h40m", "19h40m"], "travel": "1h20m"},
+	{"name": "Airship", "from": "Port Jeuno", "to": "Kazham", "departures": ["4h40m", "10h40m"], "travel": "1h20m"},
+	{"name": "Airship", "from": "Port Bastok", "to": "Port Jeuno", "departures": ["4h40m"], "travel": "1h20m"},
+	{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": ["0s", "8h", "16h"], "travel": "6h"}
+]}`
+
+func TestLoad(t *testing.T) {
+	routes, err := transport.Load(strings.NewReader(testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 4 {
+		t.Fatalf("want 4 routes, but %d", len(routes))
+	}
+	if r := routes[3]; r.String() != "Ferry: Selbina -> Mhaura" || len(r.Departures) != 3 ||
+		r.Departures[1] != transport.Clock(8, 0) || r.Travel != transport.Clock(6, 0) {
+		t.Errorf("unexpected route %+v", r)
+	}
+
+	for i, data := range []string{
+		`{"routes": [{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": [], "travel": "6h"}]}`,
+		`{"routes": [{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": ["8h", "0s"], "travel": "6h"}]}`,
+		`{"routes": [{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": ["24h"], "travel": "6h"}]}`,
+		`{"routes": [{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": ["0s"]}]}`,
+		`{"routes": [{"name": "Ferry", "to": "Mhaura", "departures": ["0s"], "travel": "6h"}]}`,
+		`{"routes": [{"name": "Ferry", "from": "Selbina", "to": "Mhaura", "departures": ["8 o'clock"], "travel": "6h"}]}`,
+		`{"routes": `,
+	} {
+		if _, err := transport.Load(strings.NewReader(data)); err == nil {
+			t.Errorf("[%d]: want error, but nil", i)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	if len(transport.Routes) != 0 {
+		t.Errorf("want no built-in routes, but %v", transport.Routes)
+	}
+
+	routes, err := transport.Load(strings.NewReader(testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.Routes = routes
+	defer func() { transport.Routes = nil }()
+
+	routes = transport.Find("port jeuno", "")
+	if len(routes) != 2 {
+		t.Fatalf("want 2 routes, but %d", len(routes))
+	}
+	for _, r := range routes {
+		if r.From != "Port Jeuno" {
+			t.Errorf("unexpected route %v", r)
+		}
+	}
+
+	routes = transport.Find("Selbina", "Mhaura")
+	if len(routes) != 1 || routes[0].Name != "Ferry" {
+		t.Errorf("unexpected routes %v", routes)
+	}
+}
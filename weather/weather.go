@@ -0,0 +1,296 @@
+// Package weather forecasts the weather of zones from weather tables
+// supplied by the program.
+//
+// A table is a simplified model of the weather of a zone: a pattern of the
+// weather of consecutive Vana'diel days, which changes at 00:00 and repeats
+// since the zero time, so that the weather of the day d is pattern[d % len].
+// It does not reproduce how the game decides the weather, and the package
+// includes no tables. The tables are read from a data file in JSON:
+//
+//     {"zones": [
+//         {"name": "Some Zone", "pattern": ["Clear", "Sunshine", "Wind", ...]},
+//         ...
+//     ]}
+//
+// and assigned to Zones to be found by name:
+//
+//     zones, err := weather.Load(f)
+//     if err != nil {
+//         return err
+//     }
+//     weather.Zones = zones
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pasela/go-vanatime"
+)
+
+// A Weather specifies a weather (Clear = 0, ...).
+type Weather int
+
+const (
+	Clear Weather = iota
+	Sunshine
+	Clouds
+	Fog
+	HotSpells
+	HeatWaves
+	Rain
+	Squalls
+	DustStorms
+	SandStorms
+	Wind
+	Gales
+	Snow
+	Blizzards
+	Thunder
+	Thunderstorms
+	Auroras
+	StellarGlare
+	Gloom
+	Darkness
+)
+
+var weatherNames = [...]string{
+	"Clear",
+	"Sunshine",
+	"Clouds",
+	"Fog",
+	"Hot Spells",
+	"Heat Waves",
+	"Rain",
+	"Squalls",
+	"Dust Storms",
+	"Sand Storms",
+	"Wind",
+	"Gales",
+	"Snow",
+	"Blizzards",
+	"Thunder",
+	"Thunderstorms",
+	"Auroras",
+	"Stellar Glare",
+	"Gloom",
+	"Darkness",
+}
+
+// the elements of the elemental weathers, from HotSpells
+var weatherElements = [...]vanatime.Element{
+	vanatime.ElementFire,
+	vanatime.ElementWater,
+	vanatime.ElementEarth,
+	vanatime.ElementWind,
+	vanatime.ElementIce,
+	vanatime.ElementLightning,
+	vanatime.ElementLight,
+	vanatime.ElementDark,
+}
+
+// String returns the English name of the weather ("Clear", "Hot Spells", ...).
+func (w Weather) String() string {
+	if Clear <= w && w <= Darkness {
+		return weatherNames[w]
+	}
+	return "%!Weather(" + fmt.Sprint(int(w)) + ")"
+}
+
+// Element returns the element of the weather. ok is false if the weather
+// has no element, such as Clear or Fog.
+func (w Weather) Element() (e vanatime.Element, ok bool) {
+	if w < HotSpells || w > Darkness {
+		return 0, false
+	}
+	return weatherElements[(w-HotSpells)/2], true
+}
+
+// Double reports whether the weather is the stronger weather of its
+// element, such as HeatWaves for Fire.
+func (w Weather) Double() bool {
+	_, ok := w.Element()
+	return ok && (w-HotSpells)%2 == 1
+}
+
+// ParseWeather returns the weather of the given name, compared ignoring
+// case and spaces.
+func ParseWeather(name string) (Weather, error) {
+	key := normalize(name)
+	for w, s := range weatherNames {
+		if normalize(s) == key {
+			return Weather(w), nil
+		}
+	}
+	return 0, errors.New("weather: unknown weather " + name)
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Replace(s, " ", "", -1))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The weather is its name.
+func (w Weather) MarshalText() ([]byte, error) {
+	if w < Clear || w > Darkness {
+		return nil, errors.New("weather: Weather.MarshalText: invalid weather " + w.String())
+	}
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The weather is expected to be a name accepted by ParseWeather.
+func (w *Weather) UnmarshalText(data []byte) error {
+	var err error
+	*w, err = ParseWeather(string(data))
+	return err
+}
+
+// A Zone is a zone and its weather pattern.
+type Zone struct {
+	Name string `json:"name"`
+
+	// Pattern is the weather of consecutive Vana'diel days, repeated
+	// since the zero time.
+	Pattern []Weather `json:"pattern"`
+}
+
+// epoch is the first day of the patterns.
+var epoch = vanatime.Date(1, 1, 1, 0, 0, 0, 0)
+
+// day returns the number of days from the zero time to the day of t.
+func day(t vanatime.Time) int64 {
+	return int64(t.Truncate(vanatime.Day).Sub(epoch) / vanatime.Day)
+}
+
+func (z *Zone) weather(day int64) Weather {
+	n := int64(len(z.Pattern))
+	return z.Pattern[(day%n+n)%n]
+}
+
+// At returns the weather of the zone at t.
+// It panics if the zone has no pattern.
+func (z *Zone) At(t vanatime.Time) Weather {
+	z.check()
+	return z.weather(day(t))
+}
+
+func (z *Zone) check() {
+	if len(z.Pattern) == 0 {
+		panic("weather: zone " + z.Name + " has no pattern")
+	}
+}
+
+// An Occurrence is a period of a weather in a zone. It starts and ends at
+// 00:00, and its Earth times are given by Interval.Earth.
+type Occurrence struct {
+	Zone    *Zone
+	Weather Weather
+	vanatime.Interval
+}
+
+// Next returns the occurrence of the weather w which is in progress at t,
+// or else the first one after t. The occurrence lasts while the weather
+// continues over consecutive days. ok is false if the pattern of the zone
+// has no w.
+func (z *Zone) Next(t vanatime.Time, w Weather) (o Occurrence, ok bool) {
+	return z.find(t, w, func(int64) bool { return true }, true)
+}
+
+// NextOnDay is like Next but finds the weather w on a day of the week of
+// the element e, such as Fire weather on Firesday, which lasts a day. ok is
+// false if the zone has no w on the days of e.
+func (z *Zone) NextOnDay(t vanatime.Time, w Weather, e vanatime.Element) (o Occurrence, ok bool) {
+	match := func(d int64) bool {
+		return epoch.Add(vanatime.Duration(d)*vanatime.Day).Weekday().Element() == e
+	}
+	return z.find(t, w, match, false)
+}
+
+// find finds the first day from the day of t on which the weather is w and
+// match reports true. If extend is true, the occurrence is extended over
+// the adjoining days of w.
+func (z *Zone) find(t vanatime.Time, w Weather, match func(day int64) bool, extend bool) (Occurrence, bool) {
+	z.check()
+
+	// the combination of the weather and the day of the week repeats
+	// every len(Pattern) * 8 days
+	first := day(t)
+	limit := int64(len(z.Pattern)) * int64(vanatime.Week/vanatime.Day)
+	for d := first; d < first+limit; d++ {
+		if z.weather(d) != w || !match(d) {
+			continue
+		}
+		start, end := d, d+1
+		if extend {
+			for start > first-limit && z.weather(start-1) == w {
+				start--
+			}
+			for end < d+limit && z.weather(end) == w {
+				end++
+			}
+		}
+		return Occurrence{
+			Zone:    z,
+			Weather: w,
+			Interval: vanatime.Interval{
+				Start: epoch.Add(vanatime.Duration(start) * vanatime.Day),
+				End:   epoch.Add(vanatime.Duration(end) * vanatime.Day),
+			},
+		}, true
+	}
+	return Occurrence{}, false
+}
+
+// NextN returns the next n occurrences of the weather w in the zone, as by
+// Next, or fewer if the zone has no w.
+func (z *Zone) NextN(t vanatime.Time, w Weather, n int) []Occurrence {
+	var list []Occurrence
+	for len(list) < n {
+		o, ok := z.Next(t, w)
+		if !ok {
+			break
+		}
+		list = append(list, o)
+		t = o.End
+	}
+	return list
+}
+
+// Zones is the weather tables searched by Find. It is empty until the
+// program assigns the tables, such as those returned by Load.
+var Zones []*Zone
+
+// Load reads weather tables in the JSON format described in the package
+// documentation.
+func Load(r io.Reader) ([]*Zone, error) {
+	var file struct {
+		Zones []*Zone `json:"zones"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("weather: %v", err)
+	}
+	for _, z := range file.Zones {
+		if z.Name == "" {
+			return nil, errors.New("weather: zone without a name")
+		}
+		if len(z.Pattern) == 0 {
+			return nil, errors.New("weather: zone " + z.Name + " has no pattern")
+		}
+	}
+	return file.Zones, nil
+}
+
+// Find returns the zone in Zones with the given name, compared ignoring
+// case.
+func Find(name string) (*Zone, bool) {
+	for _, z := range Zones {
+		if strings.EqualFold(z.Name, name) {
+			return z, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,213 @@
+package weather_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pasela/go-vanatime"
+	"github.com/pasela/go-vanatime/weather"
+)
+
+func date(month, day int) vanatime.Time {
+	return vanatime.Date(1313, month, day, 0, 0, 0, 0)
+}
+
+// 1313-04-13 is the first day of the pattern
+var testZone = &weather.Zone{
+	Name:    "Test Zone",
+	Pattern: []weather.Weather{weather.Clear, weather.Rain, weather.Rain},
+}
+
+func TestWeather(t *testing.T) {
+	tests := []struct {
+		w       weather.Weather
+		name    string
+		element vanatime.Element
+		ok      bool
+		double  bool
+	}{
+		{weather.Clear, "Clear", 0, false, false},
+		{weather.Fog, "Fog", 0, false, false},
+		{weather.HotSpells, "Hot Spells", vanatime.ElementFire, true, false},
+		{weather.HeatWaves, "Heat Waves", vanatime.ElementFire, true, true},
+		{weather.Squalls, "Squalls", vanatime.ElementWater, true, true},
+		{weather.DustStorms, "Dust Storms", vanatime.ElementEarth, true, false},
+		{weather.Wind, "Wind", vanatime.ElementWind, true, false},
+		{weather.Blizzards, "Blizzards", vanatime.ElementIce, true, true},
+		{weather.Thunder, "Thunder", vanatime.ElementLightning, true, false},
+		{weather.StellarGlare, "Stellar Glare", vanatime.ElementLight, true, true},
+		{weather.Gloom, "Gloom", vanatime.ElementDark, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.w.String(); got != tt.name {
+			t.Errorf("String() = %q; want %q", got, tt.name)
+		}
+		if e, ok := tt.w.Element(); e != tt.element || ok != tt.ok {
+			t.Errorf("%v.Element() = %v, %v; want %v, %v", tt.w, e, ok, tt.element, tt.ok)
+		}
+		if got := tt.w.Double(); got != tt.double {
+			t.Errorf("%v.Double() = %v; want %v", tt.w, got, tt.double)
+		}
+		if w, err := weather.ParseWeather(strings.ToLower(strings.Replace(tt.name, " ", "", -1))); err != nil || w != tt.w {
+			t.Errorf("ParseWeather(%q) = %v, %v; want %v", tt.name, w, err, tt.w)
+		}
+	}
+
+	if _, err := weather.ParseWeather("Meteors"); err == nil {
+		t.Error("ParseWeather(Meteors) succeeded")
+	}
+	if got := weather.Weather(20).String(); got != "%!Weather(20)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestWeatherJSON(t *testing.T) {
+	data, err := json.Marshal([]weather.Weather{weather.Clear, weather.HeatWaves})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["Clear","Heat Waves"]`; string(data) != want {
+		t.Errorf("Marshal = %s; want %s", data, want)
+	}
+	var ws []weather.Weather
+	if err := json.Unmarshal(data, &ws); err != nil || len(ws) != 2 || ws[1] != weather.HeatWaves {
+		t.Errorf("Unmarshal = %v, %v", ws, err)
+	}
+	if _, err := json.Marshal(weather.Weather(-1)); err == nil {
+		t.Error("Marshal(-1) succeeded")
+	}
+}
+
+func TestAt(t *testing.T) {
+	tests := []struct {
+		t    vanatime.Time
+		want weather.Weather
+	}{
+		{date(4, 13), weather.Clear},
+		{vanatime.Date(1313, 4, 13, 23, 59, 59, 999999), weather.Clear},
+		{date(4, 14), weather.Rain},
+		{date(4, 15), weather.Rain},
+		{date(4, 16), weather.Clear},
+		{vanatime.Date(-1, 1, 1, 0, 0, 0, 0), testZone.At(vanatime.Date(-1, 1, 4, 0, 0, 0, 0))},
+	}
+	for _, tt := range tests {
+		if got := testZone.At(tt.t); got != tt.want {
+			t.Errorf("At(%v) = %v; want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		t          vanatime.Time
+		w          weather.Weather
+		start, end vanatime.Time
+		ok         bool
+	}{
+		{vanatime.Date(1313, 4, 13, 21, 0, 0, 0), weather.Rain, date(4, 14), date(4, 16), true},
+		{vanatime.Date(1313, 4, 15, 12, 0, 0, 0), weather.Rain, date(4, 14), date(4, 16), true},
+		{date(4, 14), weather.Clear, date(4, 16), date(4, 17), true},
+		{date(4, 14), weather.Snow, vanatime.Time{}, vanatime.Time{}, false},
+	}
+	for _, tt := range tests {
+		o, ok := testZone.Next(tt.t, tt.w)
+		if ok != tt.ok || !o.Start.Equal(tt.start) || !o.End.Equal(tt.end) {
+			t.Errorf("Next(%v, %v) = %v, %v; want [%v, %v), %v", tt.t, tt.w, o.Interval, ok, tt.start, tt.end, tt.ok)
+		}
+		if ok && (o.Zone != testZone || o.Weather != tt.w) {
+			t.Errorf("Next(%v, %v) = %+v", tt.t, tt.w, o)
+		}
+	}
+
+	list := testZone.NextN(date(4, 13), weather.Rain, 3)
+	for i, want := range []vanatime.Time{date(4, 14), date(4, 17), date(4, 20)} {
+		if i >= len(list) || !list[i].Start.Equal(want) {
+			t.Errorf("NextN()[%d] = %v; want start %v", i, list, want)
+		}
+	}
+}
+
+func TestNextOnDay(t *testing.T) {
+	tests := []struct {
+		t       vanatime.Time
+		w       weather.Weather
+		element vanatime.Element
+		start   vanatime.Time
+		ok      bool
+	}{
+		{date(4, 13), weather.Rain, vanatime.ElementFire, date(4, 15), true},
+		{date(4, 13), weather.Rain, vanatime.ElementDark, date(4, 14), true},
+		{date(4, 13), weather.Clear, vanatime.ElementFire, date(5, 1), true},
+		{date(4, 13), weather.Clear, vanatime.ElementLight, date(4, 13), true},
+		{date(4, 13), weather.Gloom, vanatime.ElementDark, vanatime.Time{}, false},
+	}
+	for _, tt := range tests {
+		o, ok := testZone.NextOnDay(tt.t, tt.w, tt.element)
+		if ok != tt.ok || !o.Start.Equal(tt.start) {
+			t.Errorf("NextOnDay(%v, %v, %v) = %v, %v; want %v, %v", tt.t, tt.w, tt.element, o.Interval, ok, tt.start, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if o.Duration() != vanatime.Day || o.Start.Weekday().Element() != tt.element {
+			t.Errorf("NextOnDay(%v, %v, %v) = %v; want a day of %v", tt.t, tt.w, tt.element, o.Interval, tt.element)
+		}
+		if start, _ := o.Earth(); !start.Equal(tt.start.Earth()) {
+			t.Errorf("Earth() = %v; want %v", start, tt.start.Earth())
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	zones, err := weather.Load(strings.NewReader(`{"zones": [{"name": "Bibiki Bay", "pattern": ["Clear", "Heat Waves", "rain"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0].Name != "Bibiki Bay" || len(zones[0].Pattern) != 3 || zones[0].Pattern[2] != weather.Rain {
+		t.Errorf("Load = %+v", zones)
+	}
+
+	for _, data := range []string{
+		`{"zones": [{"name": "Bibiki Bay", "pattern": ["Meteors"]}]}`,
+		`{"zones": [{"name": "Bibiki Bay", "pattern": []}]}`,
+		`{"zones": [{"pattern": ["Clear"]}]}`,
+		`{"zones": `,
+	} {
+		if _, err := weather.Load(strings.NewReader(data)); err == nil {
+			t.Errorf("Load(%s) succeeded", data)
+		}
+	}
+}
+
+func TestZones(t *testing.T) {
+	if len(weather.Zones) != 0 {
+		t.Errorf("Zones = %v; want no built-in tables", weather.Zones)
+	}
+	if _, ok := weather.Find("Test Zone"); ok {
+		t.Error("Find(Test Zone) succeeded without tables")
+	}
+
+	// demo tables, not the weather of the game
+	zones, err := weather.Load(strings.NewReader(`{"zones": [
+		{"name": "Test Zone", "pattern": ["Clear", "Rain", "Rain"]},
+		{"name": "Test Dunes", "pattern": ["Sunshine", "Hot Spells", "Clear", "Heat Waves", "Clear"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	weather.Zones = zones
+	defer func() { weather.Zones = nil }()
+
+	z, ok := weather.Find("test dunes")
+	if !ok || z.Name != "Test Dunes" {
+		t.Fatalf("Find(test dunes) = %v, %v", z, ok)
+	}
+	if _, ok := z.NextOnDay(date(4, 13), weather.HeatWaves, vanatime.ElementFire); !ok {
+		t.Error("no Heat Waves on Firesday in Test Dunes")
+	}
+	if _, ok := weather.Find("Norg"); ok {
+		t.Error("Find(Norg) succeeded")
+	}
+}